@@ -5,4 +5,5 @@ go 1.15
 require (
 	github.com/google/go-github/v32 v32.1.0
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/text v0.3.7
 )
@@ -0,0 +1,144 @@
+package maps
+
+import (
+	"fmt"
+	"strings"
+)
+
+type OpKind int
+
+const (
+	AddOp OpKind = iota
+	UpdateOp
+	DeleteOp
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case AddOp:
+		return "add"
+	case UpdateOp:
+		return "update"
+	case DeleteOp:
+		return "delete"
+	default:
+		return fmt.Sprintf("OpKind(%d)", int(k))
+	}
+}
+
+type Operation struct {
+	Kind       OpKind
+	Word       string
+	Definition string
+}
+
+// Batch collects operations to be applied to a Dictionary all at once.
+type Batch struct {
+	operations []Operation
+}
+
+func (b *Batch) Add(word, definition string) *Batch {
+	b.operations = append(b.operations, Operation{AddOp, word, definition})
+	return b
+}
+
+func (b *Batch) Update(word, definition string) *Batch {
+	b.operations = append(b.operations, Operation{UpdateOp, word, definition})
+	return b
+}
+
+func (b *Batch) Delete(word string) *Batch {
+	b.operations = append(b.operations, Operation{DeleteOp, word, ""})
+	return b
+}
+
+func (b *Batch) Operations() []Operation {
+	return append([]Operation(nil), b.operations...)
+}
+
+type OperationError struct {
+	Index     int
+	Operation Operation
+	Err       error
+}
+
+func (e OperationError) Error() string {
+	return fmt.Sprintf("operation %d (%s %q): %v", e.Index, e.Operation.Kind, e.Operation.Word, e.Err)
+}
+
+func (e OperationError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError lists every operation that stopped a batch from being applied.
+type BatchError []OperationError
+
+func (e BatchError) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "batch not applied: " + strings.Join(messages, "; ")
+}
+
+// Apply runs every operation in b, in order, or none of them. Operations are
+// validated against the dictionary as it would be after the earlier operations
+// in the batch, and a BatchError is returned if any of them would fail.
+func (d Dictionary) Apply(b *Batch) error {
+	// pending holds the words touched by the batch; a nil definition means deleted.
+	pending := map[string]*string{}
+	lookup := func(word string) bool {
+		if definition, ok := pending[word]; ok {
+			return definition != nil
+		}
+		_, err := d.Search(word)
+		return err == nil
+	}
+
+	var errs BatchError
+	for i, op := range b.operations {
+		exists := lookup(op.Word)
+		definition := op.Definition
+
+		var err error
+		switch op.Kind {
+		case AddOp:
+			if exists {
+				err = ErrWordExists
+			} else {
+				pending[op.Word] = &definition
+			}
+		case UpdateOp:
+			if !exists {
+				err = ErrWordDoesNotExist
+			} else {
+				pending[op.Word] = &definition
+			}
+		case DeleteOp:
+			if !exists {
+				err = ErrNotFound
+			} else {
+				pending[op.Word] = nil
+			}
+		default:
+			err = DictionaryErr("unknown operation " + op.Kind.String())
+		}
+
+		if err != nil {
+			errs = append(errs, OperationError{Index: i, Operation: op, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	for word, definition := range pending {
+		if definition == nil {
+			d.Delete(word)
+		} else {
+			d[word] = *definition
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package maps
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	t.Run("applies every operation", func(t *testing.T) {
+		dictionary := Dictionary{"old": "old definition", "stale": "stale definition"}
+		batch := (&Batch{}).
+			Add("new", "new definition").
+			Update("old", "updated definition").
+			Delete("stale")
+
+		err := dictionary.Apply(batch)
+
+		assertError(t, err, nil)
+		want := Dictionary{"old": "updated definition", "new": "new definition"}
+		if !reflect.DeepEqual(dictionary, want) {
+			t.Errorf("got %v want %v", dictionary, want)
+		}
+	})
+
+	t.Run("later operations see earlier ones", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+		batch := (&Batch{}).
+			Delete("test").
+			Add("test", "re-added").
+			Update("test", "updated")
+
+		err := dictionary.Apply(batch)
+
+		assertError(t, err, nil)
+		assertDefinition(t, dictionary, "test", "updated")
+	})
+
+	t.Run("applies nothing when an operation fails", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+		batch := (&Batch{}).
+			Add("new", "new definition").
+			Add("test", "duplicate").
+			Update("missing", "definition")
+
+		err := dictionary.Apply(batch)
+
+		var batchErr BatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("expected a BatchError, got %v", err)
+		}
+		if len(batchErr) != 2 {
+			t.Fatalf("got %d operation errors want 2", len(batchErr))
+		}
+		if batchErr[0].Index != 1 || !errors.Is(batchErr[0], ErrWordExists) {
+			t.Errorf("unexpected first error %v", batchErr[0])
+		}
+		if batchErr[1].Index != 2 || !errors.Is(batchErr[1], ErrWordDoesNotExist) {
+			t.Errorf("unexpected second error %v", batchErr[1])
+		}
+
+		want := Dictionary{"test": "this is just a test"}
+		if !reflect.DeepEqual(dictionary, want) {
+			t.Errorf("got %v want %v", dictionary, want)
+		}
+	})
+}
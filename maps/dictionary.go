@@ -48,8 +48,6 @@ func (d Dictionary) Update(key string, value string) error {
 		return err
 	}
 	return nil
-
-	return nil
 }
 
 func (d Dictionary) Delete(key string) {
@@ -0,0 +1,232 @@
+package maps
+
+import (
+	"fmt"
+	"sync"
+)
+
+const ErrSequenceTruncated = DictionaryErr("cannot resume because the changes have been truncated")
+
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota
+	Updated
+	Deleted
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Updated:
+		return "updated"
+	case Deleted:
+		return "deleted"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+}
+
+// Change describes one mutation. Old is empty for Added and New is empty for
+// Deleted.
+type Change struct {
+	Seq  uint64
+	Kind ChangeKind
+	Word string
+	Old  string
+	New  string
+}
+
+// Feed wraps a Dictionary and publishes every mutation as a numbered Change.
+// Changes are retained so that subscribers can resume after reconnecting,
+// until they are dropped with Truncate.
+type Feed struct {
+	mu         sync.Mutex
+	changed    *sync.Cond
+	dictionary Dictionary
+	changes    []Change
+	last       uint64
+}
+
+func NewFeed(dictionary Dictionary) *Feed {
+	if dictionary == nil {
+		dictionary = Dictionary{}
+	}
+	f := &Feed{dictionary: dictionary}
+	f.changed = sync.NewCond(&f.mu)
+	return f
+}
+
+func (f *Feed) Search(word string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.dictionary.Search(word)
+}
+
+func (f *Feed) Add(word, definition string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.dictionary.Add(word, definition); err != nil {
+		return err
+	}
+	f.publish(Change{Kind: Added, Word: word, New: definition})
+	return nil
+}
+
+func (f *Feed) Update(word, definition string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	old, _ := f.dictionary.Search(word)
+	if err := f.dictionary.Update(word, definition); err != nil {
+		return err
+	}
+	f.publish(Change{Kind: Updated, Word: word, Old: old, New: definition})
+	return nil
+}
+
+func (f *Feed) Delete(word string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	old, err := f.dictionary.Search(word)
+	if err != nil {
+		return err
+	}
+	f.dictionary.Delete(word)
+	f.publish(Change{Kind: Deleted, Word: word, Old: old})
+	return nil
+}
+
+// LastSeq returns the sequence number of the most recent change, or 0 if
+// nothing has changed yet.
+func (f *Feed) LastSeq() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.last
+}
+
+// Truncate forgets the changes up to and including seq.
+func (f *Feed) Truncate(seq uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	dropped := 0
+	for dropped < len(f.changes) && f.changes[dropped].Seq <= seq {
+		dropped++
+	}
+	if dropped > 0 {
+		// Copy what is left so the truncated changes can be garbage collected.
+		f.changes = append([]Change(nil), f.changes[dropped:]...)
+	}
+	f.changed.Broadcast()
+}
+
+// Subscribe delivers, in order, every change with a sequence number greater
+// than after. Pass 0 to receive every retained change, or the last sequence
+// number seen to resume.
+func (f *Feed) Subscribe(after uint64) (*Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.retains(after + 1) {
+		return nil, ErrSequenceTruncated
+	}
+
+	s := &Subscription{feed: f, changes: make(chan Change), done: make(chan struct{})}
+	go s.run(after + 1)
+	return s, nil
+}
+
+func (f *Feed) publish(change Change) {
+	f.last++
+	change.Seq = f.last
+	f.changes = append(f.changes, change)
+	f.changed.Broadcast()
+}
+
+// retains reports whether the change numbered seq is either retained or yet to
+// happen.
+func (f *Feed) retains(seq uint64) bool {
+	if seq > f.last {
+		return true
+	}
+	return len(f.changes) > 0 && f.changes[0].Seq <= seq
+}
+
+// since returns the retained changes from seq onwards. The caller holds f.mu.
+func (f *Feed) since(seq uint64) []Change {
+	if len(f.changes) == 0 || seq > f.last {
+		return nil
+	}
+	start := int(seq - f.changes[0].Seq)
+	return append([]Change(nil), f.changes[start:]...)
+}
+
+// Subscription receives changes from a Feed until it is closed.
+type Subscription struct {
+	feed    *Feed
+	changes chan Change
+	done    chan struct{}
+	once    sync.Once
+	closed  bool
+	err     error
+}
+
+// Changes returns the channel changes are delivered on. It is closed when the
+// subscription ends.
+func (s *Subscription) Changes() <-chan Change {
+	return s.changes
+}
+
+// Err reports why the subscription ended on its own, such as the feed being
+// truncated past changes that had not been delivered yet.
+func (s *Subscription) Err() error {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	return s.err
+}
+
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.feed.mu.Lock()
+		s.closed = true
+		s.feed.changed.Broadcast()
+		s.feed.mu.Unlock()
+		close(s.done)
+	})
+}
+
+func (s *Subscription) run(next uint64) {
+	defer close(s.changes)
+
+	f := s.feed
+	for {
+		f.mu.Lock()
+		for next > f.last && !s.closed {
+			f.changed.Wait()
+		}
+		if s.closed {
+			f.mu.Unlock()
+			return
+		}
+		if !f.retains(next) {
+			s.err = ErrSequenceTruncated
+			f.mu.Unlock()
+			return
+		}
+		pending := f.since(next)
+		f.mu.Unlock()
+
+		for _, change := range pending {
+			select {
+			case s.changes <- change:
+				next = change.Seq + 1
+			case <-s.done:
+				return
+			}
+		}
+	}
+}
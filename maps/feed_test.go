@@ -0,0 +1,118 @@
+package maps
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFeed(t *testing.T) {
+	t.Run("emits ordered changes", func(t *testing.T) {
+		feed := NewFeed(Dictionary{})
+		subscription, err := feed.Subscribe(0)
+		assertError(t, err, nil)
+		defer subscription.Close()
+
+		feed.Add("test", "first")
+		feed.Update("test", "second")
+		feed.Delete("test")
+
+		want := []Change{
+			{Seq: 1, Kind: Added, Word: "test", New: "first"},
+			{Seq: 2, Kind: Updated, Word: "test", Old: "first", New: "second"},
+			{Seq: 3, Kind: Deleted, Word: "test", Old: "second"},
+		}
+		for _, w := range want {
+			assertChange(t, receive(t, subscription), w)
+		}
+	})
+
+	t.Run("failed mutations are not emitted", func(t *testing.T) {
+		feed := NewFeed(Dictionary{"test": "this is just a test"})
+
+		assertError(t, feed.Add("test", "duplicate"), ErrWordExists)
+		assertError(t, feed.Delete("missing"), ErrNotFound)
+
+		if got := feed.LastSeq(); got != 0 {
+			t.Errorf("got last sequence %d want 0", got)
+		}
+	})
+
+	t.Run("resumes after a sequence number", func(t *testing.T) {
+		feed := NewFeed(Dictionary{})
+		feed.Add("a", "1")
+		feed.Add("b", "2")
+		feed.Add("c", "3")
+
+		subscription, err := feed.Subscribe(2)
+		assertError(t, err, nil)
+		defer subscription.Close()
+
+		assertChange(t, receive(t, subscription), Change{Seq: 3, Kind: Added, Word: "c", New: "3"})
+	})
+
+	t.Run("cannot resume from truncated changes", func(t *testing.T) {
+		feed := NewFeed(Dictionary{})
+		feed.Add("a", "1")
+		feed.Add("b", "2")
+		feed.Truncate(1)
+
+		_, err := feed.Subscribe(0)
+		assertError(t, err, ErrSequenceTruncated)
+
+		subscription, err := feed.Subscribe(1)
+		assertError(t, err, nil)
+		defer subscription.Close()
+		assertChange(t, receive(t, subscription), Change{Seq: 2, Kind: Added, Word: "b", New: "2"})
+	})
+
+	t.Run("truncating releases the changes", func(t *testing.T) {
+		feed := NewFeed(Dictionary{})
+		for i := 0; i < 100; i++ {
+			feed.Add(strconv.Itoa(i), "definition")
+		}
+
+		feed.Truncate(98)
+
+		if len(feed.changes) != 2 || cap(feed.changes) > 2 {
+			t.Errorf("got %d changes in a slice of %d want 2 in a new slice", len(feed.changes), cap(feed.changes))
+		}
+		if feed.changes[0].Seq != 99 {
+			t.Errorf("got first change %d want 99", feed.changes[0].Seq)
+		}
+	})
+
+	t.Run("close ends the subscription", func(t *testing.T) {
+		feed := NewFeed(Dictionary{})
+		subscription, _ := feed.Subscribe(0)
+
+		subscription.Close()
+
+		select {
+		case _, ok := <-subscription.Changes():
+			if ok {
+				t.Error("expected the changes channel to be closed")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the subscription to close")
+		}
+	})
+}
+
+func receive(t *testing.T, subscription *Subscription) Change {
+	t.Helper()
+	select {
+	case change := <-subscription.Changes():
+		return change
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a change")
+		return Change{}
+	}
+}
+
+func assertChange(t *testing.T, got, want Change) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
@@ -0,0 +1,210 @@
+package maps
+
+import (
+	"strings"
+	"time"
+)
+
+const ErrRevisionNotFound = DictionaryErr("could not find the revision you were looking for")
+
+// Revision is one recorded state of a word. Deleting a word is recorded as a
+// revision with Deleted set.
+type Revision struct {
+	Number     int
+	Definition string
+	Deleted    bool
+	Author     string
+	Time       time.Time
+}
+
+type RevisionOption func(*Revision)
+
+// By records who made a change.
+func By(author string) RevisionOption {
+	return func(r *Revision) {
+		r.Author = author
+	}
+}
+
+// History wraps a Dictionary and keeps every revision of every word.
+type History struct {
+	dictionary Dictionary
+	revisions  map[string][]Revision
+	now        func() time.Time
+}
+
+// NewHistory starts tracking dictionary, recording its current words as their
+// first revision.
+func NewHistory(dictionary Dictionary) *History {
+	if dictionary == nil {
+		dictionary = Dictionary{}
+	}
+	h := &History{dictionary: dictionary, revisions: map[string][]Revision{}, now: time.Now}
+	for word, definition := range dictionary {
+		h.record(word, Revision{Definition: definition}, nil)
+	}
+	return h
+}
+
+func (h *History) Search(word string) (string, error) {
+	return h.dictionary.Search(word)
+}
+
+func (h *History) Add(word, definition string, opts ...RevisionOption) error {
+	if err := h.dictionary.Add(word, definition); err != nil {
+		return err
+	}
+	h.record(word, Revision{Definition: definition}, opts)
+	return nil
+}
+
+func (h *History) Update(word, definition string, opts ...RevisionOption) error {
+	if err := h.dictionary.Update(word, definition); err != nil {
+		return err
+	}
+	h.record(word, Revision{Definition: definition}, opts)
+	return nil
+}
+
+func (h *History) Delete(word string, opts ...RevisionOption) error {
+	if _, err := h.dictionary.Search(word); err != nil {
+		return err
+	}
+	h.dictionary.Delete(word)
+	h.record(word, Revision{Deleted: true}, opts)
+	return nil
+}
+
+// Revisions lists the revisions of word, oldest first.
+func (h *History) Revisions(word string) ([]Revision, error) {
+	revisions, ok := h.revisions[word]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]Revision(nil), revisions...), nil
+}
+
+// Diff compares the definitions of two revisions of word word by word.
+func (h *History) Diff(word string, from, to int) ([]Edit, error) {
+	a, err := h.revision(word, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := h.revision(word, to)
+	if err != nil {
+		return nil, err
+	}
+	return DiffText(a.Definition, b.Definition), nil
+}
+
+// Rollback restores word to an earlier revision by recording it again. Rolling
+// back to a deleted revision deletes the word, or does nothing if it is
+// already deleted, and rolling back a deleted word restores it.
+func (h *History) Rollback(word string, number int, opts ...RevisionOption) error {
+	target, err := h.revision(word, number)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.dictionary.Search(word)
+	deleted := err == ErrNotFound
+	switch {
+	case target.Deleted && deleted:
+		return nil
+	case target.Deleted:
+		return h.Delete(word, opts...)
+	case deleted:
+		return h.Add(word, target.Definition, opts...)
+	}
+	return h.Update(word, target.Definition, opts...)
+}
+
+func (h *History) revision(word string, number int) (Revision, error) {
+	revisions, ok := h.revisions[word]
+	if !ok {
+		return Revision{}, ErrNotFound
+	}
+	if number < 1 || number > len(revisions) {
+		return Revision{}, ErrRevisionNotFound
+	}
+	return revisions[number-1], nil
+}
+
+func (h *History) record(word string, revision Revision, opts []RevisionOption) {
+	revision.Number = len(h.revisions[word]) + 1
+	revision.Time = h.now()
+	for _, opt := range opts {
+		opt(&revision)
+	}
+	h.revisions[word] = append(h.revisions[word], revision)
+}
+
+type EditOp int
+
+const (
+	EditEqual EditOp = iota
+	EditInsert
+	EditRemove
+)
+
+func (op EditOp) String() string {
+	switch op {
+	case EditInsert:
+		return "+"
+	case EditRemove:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+type Edit struct {
+	Op   EditOp
+	Text string
+}
+
+// DiffText returns the word-level edits that turn a into b.
+func DiffText(a, b string) []Edit {
+	from, to := strings.Fields(a), strings.Fields(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of from[i:] and to[j:].
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var edits []Edit
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			edits = append(edits, Edit{EditEqual, from[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, Edit{EditRemove, from[i]})
+			i++
+		default:
+			edits = append(edits, Edit{EditInsert, to[j]})
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		edits = append(edits, Edit{EditRemove, from[i]})
+	}
+	for ; j < len(to); j++ {
+		edits = append(edits, Edit{EditInsert, to[j]})
+	}
+	return edits
+}
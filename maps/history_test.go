@@ -0,0 +1,120 @@
+package maps
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	t.Run("records revisions with author and time", func(t *testing.T) {
+		history := newTestHistory(Dictionary{"test": "this is just a test"})
+
+		err := history.Update("test", "new definition", By("alice"))
+		assertError(t, err, nil)
+
+		revisions, err := history.Revisions("test")
+		assertError(t, err, nil)
+		if len(revisions) != 2 {
+			t.Fatalf("got %d revisions want 2", len(revisions))
+		}
+		if revisions[1].Number != 2 || revisions[1].Author != "alice" || revisions[1].Definition != "new definition" {
+			t.Errorf("unexpected revision %+v", revisions[1])
+		}
+		if want := time.Date(2021, 1, 1, 0, 0, 1, 0, time.UTC); !revisions[1].Time.Equal(want) {
+			t.Errorf("got time %v want %v", revisions[1].Time, want)
+		}
+	})
+
+	t.Run("failed updates are not recorded", func(t *testing.T) {
+		history := newTestHistory(Dictionary{})
+
+		err := history.Update("test", "definition")
+		assertError(t, err, ErrWordDoesNotExist)
+
+		_, err = history.Revisions("test")
+		assertError(t, err, ErrNotFound)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		history := newTestHistory(Dictionary{})
+		history.Add("test", "first")
+		history.Update("test", "second")
+
+		err := history.Rollback("test", 1)
+		assertError(t, err, nil)
+
+		got, _ := history.Search("test")
+		assertStrings(t, got, "first")
+		revisions, _ := history.Revisions("test")
+		if len(revisions) != 3 {
+			t.Errorf("got %d revisions want 3", len(revisions))
+		}
+	})
+
+	t.Run("undo delete", func(t *testing.T) {
+		history := newTestHistory(Dictionary{"test": "this is just a test"})
+
+		err := history.Delete("test", By("bob"))
+		assertError(t, err, nil)
+		_, err = history.Search("test")
+		assertError(t, err, ErrNotFound)
+
+		revisions, _ := history.Revisions("test")
+		if !revisions[1].Deleted || revisions[1].Author != "bob" {
+			t.Errorf("expected a delete revision, got %+v", revisions[1])
+		}
+
+		err = history.Rollback("test", 1)
+		assertError(t, err, nil)
+		got, _ := history.Search("test")
+		assertStrings(t, got, "this is just a test")
+	})
+
+	t.Run("rollback to a deletion of a deleted word", func(t *testing.T) {
+		history := newTestHistory(Dictionary{"test": "this is just a test"})
+		history.Delete("test")
+		history.Rollback("test", 1)
+		history.Delete("test")
+
+		err := history.Rollback("test", 2)
+		assertError(t, err, nil)
+
+		_, err = history.Search("test")
+		assertError(t, err, ErrNotFound)
+		revisions, _ := history.Revisions("test")
+		if len(revisions) != 4 {
+			t.Errorf("got %d revisions want 4", len(revisions))
+		}
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		history := newTestHistory(Dictionary{"test": "this is just a test"})
+
+		err := history.Rollback("test", 5)
+		assertError(t, err, ErrRevisionNotFound)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		history := newTestHistory(Dictionary{"test": "a small test"})
+		history.Update("test", "a big test")
+
+		got, err := history.Diff("test", 1, 2)
+		assertError(t, err, nil)
+
+		want := []Edit{{EditEqual, "a"}, {EditRemove, "small"}, {EditInsert, "big"}, {EditEqual, "test"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+}
+
+func newTestHistory(dictionary Dictionary) *History {
+	clock := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := NewHistory(dictionary)
+	history.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	return history
+}
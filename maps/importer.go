@@ -0,0 +1,333 @@
+package maps
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	ErrMalformedEntry  = DictionaryErr("entry is malformed")
+	ErrEmptyDefinition = DictionaryErr("entry has no definition")
+	ErrMetadataEntry   = DictionaryErr("entry is dictionary metadata")
+)
+
+const defaultProgressEvery = 1000
+
+// Adder is anything words can be imported into, such as a Dictionary or a
+// StoredDictionary backed by a LogStore for imports larger than memory.
+type Adder interface {
+	Add(word, definition string) error
+}
+
+type ImportOptions struct {
+	// Progress, if set, is called every ProgressEvery entries and once more
+	// when the import finishes.
+	Progress      func(ImportProgress)
+	ProgressEvery int
+}
+
+type ImportProgress struct {
+	Entries   int
+	BytesRead int64
+	// TotalBytes is the size of the input, or zero if it is not known.
+	TotalBytes int64
+}
+
+// SkippedEntry is an entry that was not imported. Line is set for entries of
+// a dictd index and Offset, the byte offset the page starts at, for XML.
+type SkippedEntry struct {
+	Line   int
+	Offset int64
+	Word   string
+	Reason error
+}
+
+type ImportReport struct {
+	Imported int
+	Skipped  []SkippedEntry
+}
+
+// ImportDictd imports a dictd dictionary from its index and its uncompressed
+// data file. The index is read as a stream and each definition is read from
+// dict on demand, so neither has to fit in memory.
+func ImportDictd(into Adder, index io.Reader, dict io.ReaderAt, options ImportOptions) (ImportReport, error) {
+	var report ImportReport
+	counter := &countingReader{r: index}
+	progress := newProgressReporter(options, counter)
+
+	scanner := bufio.NewScanner(counter)
+	line := 0
+	for scanner.Scan() {
+		line++
+		word, definition, err := readDictdEntry(scanner.Text(), dict)
+		report.add(into, SkippedEntry{Line: line, Word: word}, definition, err)
+		progress.entry()
+	}
+	progress.done()
+
+	return report, scanner.Err()
+}
+
+// ImportDictdFiles imports the dictd dictionary stored in indexPath and dictPath.
+// Compressed .dict.dz files must be decompressed first.
+func ImportDictdFiles(into Adder, indexPath, dictPath string, options ImportOptions) (ImportReport, error) {
+	index, err := os.Open(indexPath)
+	if err != nil {
+		return ImportReport{}, err
+	}
+	defer index.Close()
+
+	dict, err := os.Open(dictPath)
+	if err != nil {
+		return ImportReport{}, err
+	}
+	defer dict.Close()
+	info, err := dict.Stat()
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	return ImportDictd(into, index, io.NewSectionReader(dict, 0, info.Size()), withTotalBytes(options, index))
+}
+
+func readDictdEntry(line string, dict io.ReaderAt) (string, string, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) < 3 {
+		return "", "", ErrMalformedEntry
+	}
+	word := fields[0]
+	if strings.HasPrefix(word, "00-database-") || strings.HasPrefix(word, "00database") {
+		return word, "", ErrMetadataEntry
+	}
+
+	offset, err := decodeDictdNumber(fields[1])
+	if err != nil {
+		return word, "", err
+	}
+	length, err := decodeDictdNumber(fields[2])
+	if err != nil {
+		return word, "", err
+	}
+
+	if sized, ok := dict.(sizedReaderAt); ok && (offset > sized.Size() || length > sized.Size()-offset) {
+		return word, "", fmt.Errorf("%w: definition at offset %d runs past the end of the dictionary", ErrMalformedEntry, offset)
+	}
+
+	// Copying grows the definition as it is read, so a bad length cannot
+	// allocate more than the dictionary holds.
+	var definition strings.Builder
+	n, err := io.Copy(&definition, io.NewSectionReader(dict, offset, length))
+	if err != nil {
+		return word, "", fmt.Errorf("%w: definition at offset %d: %v", ErrMalformedEntry, offset, err)
+	}
+	if n < length {
+		return word, "", fmt.Errorf("%w: definition at offset %d runs past the end of the dictionary", ErrMalformedEntry, offset)
+	}
+	return word, cleanDictdDefinition(word, definition.String()), nil
+}
+
+// sizedReaderAt is a dictionary whose size is known, such as a
+// *strings.Reader or an *io.SectionReader, so entries past its end are caught
+// before they are read.
+type sizedReaderAt interface {
+	io.ReaderAt
+	Size() int64
+}
+
+const dictdDigits = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// decodeDictdNumber decodes the base 64 numbers dictd indexes use for offsets
+// and lengths.
+func decodeDictdNumber(s string) (int64, error) {
+	if s == "" || len(s) > 10 {
+		return 0, fmt.Errorf("%w: bad number %q", ErrMalformedEntry, s)
+	}
+	var n int64
+	for _, c := range s {
+		digit := strings.IndexRune(dictdDigits, c)
+		if digit < 0 {
+			return 0, fmt.Errorf("%w: bad number %q", ErrMalformedEntry, s)
+		}
+		n = n*64 + int64(digit)
+	}
+	return n, nil
+}
+
+// cleanDictdDefinition drops the headword line dictd definitions usually start
+// with and joins the rest into a single line.
+func cleanDictdDefinition(word, text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) > 1 && strings.EqualFold(strings.TrimSpace(lines[0]), word) {
+		lines = lines[1:]
+	}
+	return strings.Join(strings.Fields(strings.Join(lines, " ")), " ")
+}
+
+type wikiPage struct {
+	Title     string `xml:"title"`
+	Namespace int    `xml:"ns"`
+	Text      string `xml:"revision>text"`
+}
+
+// ImportWiktionaryXML imports a MediaWiki XML extract of Wiktionary. Only main
+// namespace pages are imported, and their numbered definition lines ("# ...")
+// become the definition. Pages are decoded one at a time so the extract does
+// not have to fit in memory.
+func ImportWiktionaryXML(into Adder, r io.Reader, options ImportOptions) (ImportReport, error) {
+	var report ImportReport
+	counter := &countingReader{r: r}
+	progress := newProgressReporter(options, counter)
+	decoder := xml.NewDecoder(counter)
+
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			progress.done()
+			return report, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "page" {
+			continue
+		}
+
+		var page wikiPage
+		if err := decoder.DecodeElement(&page, &start); err != nil {
+			progress.done()
+			return report, err
+		}
+		word, definition, err := readWikiPage(page)
+		report.add(into, SkippedEntry{Offset: offset, Word: word}, definition, err)
+		progress.entry()
+	}
+	progress.done()
+
+	return report, nil
+}
+
+// ImportWiktionaryFile imports the Wiktionary XML extract stored in path.
+func ImportWiktionaryFile(into Adder, path string, options ImportOptions) (ImportReport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ImportReport{}, err
+	}
+	defer file.Close()
+
+	return ImportWiktionaryXML(into, file, withTotalBytes(options, file))
+}
+
+func readWikiPage(page wikiPage) (string, string, error) {
+	word := strings.TrimSpace(page.Title)
+	if word == "" {
+		return "", "", ErrMalformedEntry
+	}
+	if page.Namespace != 0 {
+		return word, "", ErrMetadataEntry
+	}
+
+	var definitions []string
+	for _, line := range strings.Split(page.Text, "\n") {
+		if !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		if definition := stripWikiMarkup(line[2:]); definition != "" {
+			definitions = append(definitions, definition)
+		}
+	}
+	return word, strings.Join(definitions, "; "), nil
+}
+
+var (
+	wikiTemplate = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+	wikiLink     = regexp.MustCompile(`\[\[(?:[^\[\]|]*\|)?([^\[\]|]*)\]\]`)
+	wikiEmphasis = regexp.MustCompile(`'{2,}`)
+)
+
+func stripWikiMarkup(text string) string {
+	for wikiTemplate.MatchString(text) {
+		text = wikiTemplate.ReplaceAllString(text, "")
+	}
+	text = wikiLink.ReplaceAllString(text, "$1")
+	text = wikiEmphasis.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// add imports entry.Word unless reading it failed, recording it as skipped if
+// it could not be imported.
+func (r *ImportReport) add(into Adder, entry SkippedEntry, definition string, err error) {
+	if err == nil && definition == "" {
+		err = ErrEmptyDefinition
+	}
+	if err == nil {
+		err = into.Add(entry.Word, definition)
+	}
+	if err != nil {
+		entry.Reason = err
+		r.Skipped = append(r.Skipped, entry)
+		return
+	}
+	r.Imported++
+}
+
+func withTotalBytes(options ImportOptions, file *os.File) ImportOptions {
+	info, err := file.Stat()
+	if err != nil || options.Progress == nil {
+		return options
+	}
+	progress := options.Progress
+	options.Progress = func(p ImportProgress) {
+		p.TotalBytes = info.Size()
+		progress(p)
+	}
+	return options
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type progressReporter struct {
+	options ImportOptions
+	counter *countingReader
+	entries int
+}
+
+func newProgressReporter(options ImportOptions, counter *countingReader) *progressReporter {
+	if options.ProgressEvery <= 0 {
+		options.ProgressEvery = defaultProgressEvery
+	}
+	return &progressReporter{options: options, counter: counter}
+}
+
+func (p *progressReporter) entry() {
+	p.entries++
+	if p.entries%p.options.ProgressEvery == 0 {
+		p.report()
+	}
+}
+
+func (p *progressReporter) done() {
+	p.report()
+}
+
+func (p *progressReporter) report() {
+	if p.options.Progress != nil {
+		p.options.Progress(ImportProgress{Entries: p.entries, BytesRead: p.counter.n})
+	}
+}
@@ -0,0 +1,157 @@
+package maps
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportDictd(t *testing.T) {
+	var dict strings.Builder
+	var index strings.Builder
+	addEntry := func(word, text string) {
+		index.WriteString(word + "\t" + encodeDictdNumber(int64(dict.Len())) + "\t" + encodeDictdNumber(int64(len(text))) + "\n")
+		dict.WriteString(text)
+	}
+	addEntry("00-database-short", "Test dictionary\n")
+	addEntry("apple", "apple\n   A round fruit\n   of the rose family.\n")
+	addEntry("test", "test\n   This is just a test.\n")
+	addEntry("test", "test\n   A second sense.\n")
+	index.WriteString("broken line\n")
+	index.WriteString("far\tZZZZ\tB\n")
+
+	t.Run("imports entries and reports skipped ones", func(t *testing.T) {
+		dictionary := Dictionary{}
+		var progress []ImportProgress
+
+		report, err := ImportDictd(dictionary, strings.NewReader(index.String()), strings.NewReader(dict.String()), ImportOptions{
+			Progress:      func(p ImportProgress) { progress = append(progress, p) },
+			ProgressEvery: 2,
+		})
+
+		assertError(t, err, nil)
+		want := Dictionary{"apple": "A round fruit of the rose family.", "test": "This is just a test."}
+		if !reflect.DeepEqual(dictionary, want) {
+			t.Errorf("got %v want %v", dictionary, want)
+		}
+		if report.Imported != 2 {
+			t.Errorf("got %d imported want 2", report.Imported)
+		}
+		assertSkipped(t, report.Skipped, []error{ErrMetadataEntry, ErrWordExists, ErrMalformedEntry, ErrMalformedEntry})
+		if report.Skipped[1].Line != 4 {
+			t.Errorf("got line %d want 4", report.Skipped[1].Line)
+		}
+		if len(progress) != 4 || progress[3].Entries != 6 || progress[3].BytesRead != int64(index.Len()) {
+			t.Errorf("unexpected progress %+v", progress)
+		}
+	})
+
+	t.Run("lengths past the end of the dictionary", func(t *testing.T) {
+		index := "huge\tA\t//////\nlong\tA\tG\n"
+		for name, dict := range map[string]io.ReaderAt{
+			"sized":   strings.NewReader("short"),
+			"unsized": struct{ io.ReaderAt }{strings.NewReader("short")},
+		} {
+			report, err := ImportDictd(Dictionary{}, strings.NewReader(index), dict, ImportOptions{})
+
+			assertError(t, err, nil)
+			if report.Imported != 0 {
+				t.Errorf("%s: got %d imported want 0", name, report.Imported)
+			}
+			assertSkipped(t, report.Skipped, []error{ErrMalformedEntry, ErrMalformedEntry})
+		}
+	})
+
+	t.Run("from files", func(t *testing.T) {
+		dir := t.TempDir()
+		indexPath, dictPath := filepath.Join(dir, "test.index"), filepath.Join(dir, "test.dict")
+		ioutil.WriteFile(indexPath, []byte(index.String()), 0644)
+		ioutil.WriteFile(dictPath, []byte(dict.String()), 0644)
+		var last ImportProgress
+
+		report, err := ImportDictdFiles(Dictionary{}, indexPath, dictPath, ImportOptions{
+			Progress: func(p ImportProgress) { last = p },
+		})
+
+		assertError(t, err, nil)
+		if report.Imported != 2 {
+			t.Errorf("got %d imported want 2", report.Imported)
+		}
+		if last.TotalBytes != int64(index.Len()) || last.BytesRead != last.TotalBytes {
+			t.Errorf("unexpected progress %+v", last)
+		}
+	})
+}
+
+func TestImportWiktionaryXML(t *testing.T) {
+	extract := `<mediawiki>
+  <page>
+    <title>apple</title>
+    <ns>0</ns>
+    <revision><text>==English==
+===Noun===
+{{en-noun}}
+# A common, round [[fruit]] of the {{taxlink|Malus|genus}} tree.
+#: ''An apple a day keeps the doctor away.''
+# The [[apple tree|tree]] itself.
+</text></revision>
+  </page>
+  <page>
+    <title>Wiktionary:About</title>
+    <ns>4</ns>
+    <revision><text># not a word</text></revision>
+  </page>
+  <page>
+    <title>empty</title>
+    <ns>0</ns>
+    <revision><text>==English==</text></revision>
+  </page>
+</mediawiki>`
+
+	dictionary := Dictionary{}
+	report, err := ImportWiktionaryXML(dictionary, strings.NewReader(extract), ImportOptions{})
+
+	assertError(t, err, nil)
+	want := Dictionary{"apple": "A common, round fruit of the tree.; The tree itself."}
+	if !reflect.DeepEqual(dictionary, want) {
+		t.Errorf("got %v want %v", dictionary, want)
+	}
+	assertSkipped(t, report.Skipped, []error{ErrMetadataEntry, ErrEmptyDefinition})
+	if offset := report.Skipped[0].Offset; !strings.HasPrefix(extract[offset:], "<page>") {
+		t.Errorf("offset %d does not point at a page", offset)
+	}
+
+	t.Run("invalid XML", func(t *testing.T) {
+		_, err := ImportWiktionaryXML(Dictionary{}, strings.NewReader("<mediawiki><page>"), ImportOptions{})
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func encodeDictdNumber(n int64) string {
+	if n == 0 {
+		return "A"
+	}
+	var digits []byte
+	for ; n > 0; n /= 64 {
+		digits = append([]byte{dictdDigits[n%64]}, digits...)
+	}
+	return string(digits)
+}
+
+func assertSkipped(t *testing.T, skipped []SkippedEntry, want []error) {
+	t.Helper()
+	if len(skipped) != len(want) {
+		t.Fatalf("got %d skipped entries want %d: %+v", len(skipped), len(want), skipped)
+	}
+	for i, entry := range skipped {
+		if !errors.Is(entry.Reason, want[i]) {
+			t.Errorf("skipped entry %d: got %v want %v", i, entry.Reason, want[i])
+		}
+	}
+}
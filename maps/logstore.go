@@ -0,0 +1,517 @@
+package maps
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const ErrStoreClosed = DictionaryErr("store is closed")
+
+const (
+	opPut    byte = 1
+	opRemove byte = 2
+
+	// recordHeaderSize is a CRC-32 of the rest of the record followed by the
+	// payload length.
+	recordHeaderSize = 8
+
+	defaultCompactRatio   = 0.5
+	defaultMinCompactSize = 1 << 20
+)
+
+type LogStoreOptions struct {
+	// SyncWrites flushes every write to disk before returning.
+	SyncWrites bool
+	// CompactRatio is the share of the log taken by overwritten or removed
+	// records that triggers a background compaction. Zero means 0.5 and a
+	// negative value disables background compaction.
+	CompactRatio float64
+	// MinCompactSize is the log size, in bytes, below which no background
+	// compaction happens. Zero means 1 MiB.
+	MinCompactSize int64
+	// OnCompactError, if set, is called with each error from a background
+	// compaction as it happens. Otherwise they are only seen when Close
+	// returns the first of them.
+	OnCompactError func(error)
+}
+
+// LogStore appends every change to a file and keeps an in-memory index of
+// where each definition lives. Opening a log replays it, discarding a torn
+// record left by a crash, and compaction rewrites it with only the live words.
+type LogStore struct {
+	mu sync.RWMutex
+	// compacting is held for a whole compaction, so only one runs at a time
+	// and the file is not swapped or closed under it.
+	compacting sync.Mutex
+
+	path    string
+	options LogStoreOptions
+	file    *os.File
+	index   map[string]location
+	size    int64
+	live    int64
+
+	compactions chan struct{}
+	done        chan struct{}
+	stopped     sync.WaitGroup
+	compactErr  error
+	// closed is set by the first Close, before the file is, so later calls
+	// don't close done again.
+	closed bool
+
+	// copied, if set, is called by compactions once the live words are
+	// copied, for tests to write while they run.
+	copied func()
+}
+
+// location is where a definition is stored and the size of the record holding it.
+type location struct {
+	offset int64
+	length int
+	record int64
+}
+
+func OpenLogStore(path string, options LogStoreOptions) (*LogStore, error) {
+	if options.CompactRatio == 0 {
+		options.CompactRatio = defaultCompactRatio
+	}
+	if options.MinCompactSize == 0 {
+		options.MinCompactSize = defaultMinCompactSize
+	}
+
+	// A leftover compaction file means a compaction was interrupted before it
+	// replaced the log, so the log itself is still complete.
+	if err := os.Remove(compactionPath(path)); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &LogStore{
+		path:        path,
+		options:     options,
+		file:        file,
+		compactions: make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	if err := s.recover(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	s.stopped.Add(1)
+	go s.compactInBackground()
+	return s, nil
+}
+
+func (s *LogStore) Load(word string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.file == nil {
+		return "", false, ErrStoreClosed
+	}
+	loc, ok := s.index[word]
+	if !ok {
+		return "", false, nil
+	}
+	definition, err := s.read(loc)
+	return definition, err == nil, err
+}
+
+func (s *LogStore) Store(word, definition string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offset, size, err := s.append(opPut, word, definition)
+	if err != nil {
+		return err
+	}
+	s.forget(word)
+	s.index[word] = location{offset: offset + size - int64(len(definition)), length: len(definition), record: size}
+	s.live += size
+	s.maybeCompact()
+	return nil
+}
+
+func (s *LogStore) Remove(word string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.index[word]; !ok {
+		return nil
+	}
+	if _, _, err := s.append(opRemove, word, ""); err != nil {
+		return err
+	}
+	s.forget(word)
+	s.maybeCompact()
+	return nil
+}
+
+func (s *LogStore) Range(fn func(word, definition string) bool) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.file == nil {
+		return ErrStoreClosed
+	}
+	words := make([]string, 0, len(s.index))
+	for word := range s.index {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	for _, word := range words {
+		definition, err := s.read(s.index[word])
+		if err != nil {
+			return err
+		}
+		if !fn(word, definition) {
+			break
+		}
+	}
+	return nil
+}
+
+// Compact rewrites the log so it only holds the live words. The new log is
+// written beside the old one and renamed over it, so a crash part way through
+// leaves the old log intact. Loads and stores carry on while the live words
+// are copied and only wait for the new log to be swapped in.
+func (s *LogStore) Compact() error {
+	s.compacting.Lock()
+	defer s.compacting.Unlock()
+	return s.compact()
+}
+
+func (s *LogStore) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrStoreClosed
+	}
+	s.closed = true
+	close(s.done)
+	s.mu.Unlock()
+	s.stopped.Wait()
+
+	s.compacting.Lock()
+	defer s.compacting.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.file.Close()
+	s.file = nil
+	if s.compactErr != nil {
+		return s.compactErr
+	}
+	return err
+}
+
+func (s *LogStore) recover() error {
+	s.index = map[string]location{}
+	s.size, s.live = 0, 0
+
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(s.file)
+	for {
+		op, word, definition, size, err := readRecord(reader, info.Size()-s.size)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			// A torn or corrupt record can only be the last thing written
+			// before a crash, so drop it and everything after it.
+			if err := s.file.Truncate(s.size); err != nil {
+				return err
+			}
+			return s.file.Sync()
+		}
+
+		s.forget(word)
+		if op == opPut {
+			s.index[word] = location{offset: s.size + size - int64(len(definition)), length: len(definition), record: size}
+			s.live += size
+		}
+		s.size += size
+	}
+}
+
+func (s *LogStore) append(op byte, word, definition string) (int64, int64, error) {
+	if s.file == nil {
+		return 0, 0, ErrStoreClosed
+	}
+
+	record := encodeRecord(op, word, definition)
+	offset := s.size
+	if _, err := s.file.WriteAt(record, offset); err != nil {
+		s.file.Truncate(offset)
+		return 0, 0, err
+	}
+	if s.options.SyncWrites {
+		if err := s.file.Sync(); err != nil {
+			return 0, 0, err
+		}
+	}
+	s.size += int64(len(record))
+	return offset, int64(len(record)), nil
+}
+
+func (s *LogStore) read(loc location) (string, error) {
+	return readDefinition(s.file, loc)
+}
+
+func readDefinition(file *os.File, loc location) (string, error) {
+	buf := make([]byte, loc.length)
+	if _, err := file.ReadAt(buf, loc.offset); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// forget drops word from the index, leaving its record as garbage.
+func (s *LogStore) forget(word string) {
+	if loc, ok := s.index[word]; ok {
+		s.live -= loc.record
+		delete(s.index, word)
+	}
+}
+
+func (s *LogStore) maybeCompact() {
+	if s.options.CompactRatio < 0 || s.size < s.options.MinCompactSize {
+		return
+	}
+	if float64(s.size-s.live)/float64(s.size) < s.options.CompactRatio {
+		return
+	}
+	select {
+	case s.compactions <- struct{}{}:
+	default:
+	}
+}
+
+func (s *LogStore) compactInBackground() {
+	defer s.stopped.Done()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.compactions:
+			s.compacting.Lock()
+			err := s.compact()
+			s.compacting.Unlock()
+			if err == nil {
+				continue
+			}
+			s.mu.Lock()
+			if s.compactErr == nil {
+				s.compactErr = err
+			}
+			s.mu.Unlock()
+			if s.options.OnCompactError != nil {
+				s.options.OnCompactError(err)
+			}
+		}
+	}
+}
+
+// compact must be called holding s.compacting. Records are never changed once
+// written, so the live ones are copied from a snapshot of the index without
+// holding s.mu, and the lock is only taken to catch up with the records
+// written meanwhile and swap the logs.
+func (s *LogStore) compact() error {
+	s.mu.RLock()
+	if s.file == nil {
+		s.mu.RUnlock()
+		return ErrStoreClosed
+	}
+	file, copiedSize := s.file, s.size
+	snapshot := make(map[string]location, len(s.index))
+	for word, loc := range s.index {
+		snapshot[word] = loc
+	}
+	s.mu.RUnlock()
+
+	tmpPath := compactionPath(s.path)
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	abandon := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	words := make([]string, 0, len(snapshot))
+	for word := range snapshot {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	compacted := &compactedLog{writer: bufio.NewWriter(tmp), index: make(map[string]location, len(words))}
+	for _, word := range words {
+		definition, err := readDefinition(file, snapshot[word])
+		if err != nil {
+			return abandon(err)
+		}
+		if err := compacted.add(opPut, word, definition); err != nil {
+			return abandon(err)
+		}
+	}
+	if s.copied != nil {
+		s.copied()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return abandon(ErrStoreClosed)
+	}
+
+	tail := bufio.NewReader(io.NewSectionReader(s.file, copiedSize, s.size-copiedSize))
+	for remaining := s.size - copiedSize; remaining > 0; {
+		op, word, definition, size, err := readRecord(tail, remaining)
+		if err != nil {
+			return abandon(err)
+		}
+		if err := compacted.add(op, word, definition); err != nil {
+			return abandon(err)
+		}
+		remaining -= size
+	}
+
+	if err := compacted.writer.Flush(); err != nil {
+		return abandon(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return abandon(err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return abandon(err)
+	}
+	syncDir(filepath.Dir(s.path))
+
+	s.file.Close()
+	s.file = tmp
+	s.index = compacted.index
+	s.size = compacted.size
+	s.live = 0
+	for _, loc := range s.index {
+		s.live += loc.record
+	}
+	return nil
+}
+
+// compactedLog is the new log a compaction writes and the index into it.
+type compactedLog struct {
+	writer *bufio.Writer
+	index  map[string]location
+	size   int64
+}
+
+func (c *compactedLog) add(op byte, word, definition string) error {
+	if _, ok := c.index[word]; op == opRemove && !ok {
+		return nil
+	}
+	record := encodeRecord(op, word, definition)
+	if _, err := c.writer.Write(record); err != nil {
+		return err
+	}
+	length := int64(len(record))
+	delete(c.index, word)
+	if op == opPut {
+		c.index[word] = location{offset: c.size + length - int64(len(definition)), length: len(definition), record: length}
+	}
+	c.size += length
+	return nil
+}
+
+func compactionPath(path string) string {
+	return path + ".compact"
+}
+
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
+func encodeRecord(op byte, word, definition string) []byte {
+	payload := make([]byte, 0, 1+binary.MaxVarintLen64+len(word)+len(definition))
+	payload = append(payload, op)
+	payload = appendUvarint(payload, uint64(len(word)))
+	payload = append(payload, word...)
+	payload = append(payload, definition...)
+
+	record := make([]byte, recordHeaderSize, recordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record[4:], uint32(len(payload)))
+	record = append(record, payload...)
+	binary.BigEndian.PutUint32(record[:4], crc32.ChecksumIEEE(record[4:]))
+	return record
+}
+
+const errCorruptRecord = DictionaryErr("corrupt log record")
+
+// readRecord decodes the next record and returns its size in bytes. It returns
+// io.EOF only at a clean end of the log. remaining bounds the record size so a
+// corrupt length cannot cause a huge allocation.
+func readRecord(r io.Reader, remaining int64) (op byte, word, definition string, size int64, err error) {
+	header := make([]byte, recordHeaderSize)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return
+	}
+
+	length := binary.BigEndian.Uint32(header[4:])
+	if int64(length) > remaining-recordHeaderSize {
+		err = errCorruptRecord
+		return
+	}
+	payload := make([]byte, length)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return
+	}
+
+	checksum := crc32.NewIEEE()
+	checksum.Write(header[4:])
+	checksum.Write(payload)
+	if checksum.Sum32() != binary.BigEndian.Uint32(header[:4]) || len(payload) == 0 {
+		err = errCorruptRecord
+		return
+	}
+
+	op = payload[0]
+	wordLength, n := binary.Uvarint(payload[1:])
+	if n <= 0 || uint64(len(payload)-1-n) < wordLength || (op != opPut && op != opRemove) {
+		err = fmt.Errorf("%w: bad payload", errCorruptRecord)
+		return
+	}
+	start := 1 + n
+	word = string(payload[start : start+int(wordLength)])
+	definition = string(payload[start+int(wordLength):])
+	size = int64(recordHeaderSize + len(payload))
+	return
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], v)
+	return append(buf, scratch[:n]...)
+}
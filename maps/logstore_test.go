@@ -0,0 +1,247 @@
+package maps
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLogStore(t *testing.T) {
+	t.Run("survives reopening", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "words.log")
+		store := openLogStore(t, path, LogStoreOptions{SyncWrites: true})
+		store.Store("a", "first")
+		store.Store("b", "second")
+		store.Store("a", "updated")
+		store.Remove("b")
+		assertError(t, store.Close(), nil)
+
+		store = openLogStore(t, path, LogStoreOptions{})
+		defer store.Close()
+
+		assertStoreContents(t, store, Dictionary{"a": "updated"})
+	})
+
+	t.Run("recovers from a torn write", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "words.log")
+		store := openLogStore(t, path, LogStoreOptions{})
+		store.Store("a", "first")
+		store.Store("b", "second")
+		store.Close()
+
+		info, _ := os.Stat(path)
+		if err := os.Truncate(path, info.Size()-3); err != nil {
+			t.Fatal(err)
+		}
+
+		store = openLogStore(t, path, LogStoreOptions{})
+		assertStoreContents(t, store, Dictionary{"a": "first"})
+
+		store.Store("c", "third")
+		store.Close()
+
+		store = openLogStore(t, path, LogStoreOptions{})
+		defer store.Close()
+		assertStoreContents(t, store, Dictionary{"a": "first", "c": "third"})
+	})
+
+	t.Run("ignores a corrupt record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "words.log")
+		store := openLogStore(t, path, LogStoreOptions{})
+		store.Store("a", "first")
+		store.Store("b", "second")
+		store.Close()
+
+		file, _ := os.OpenFile(path, os.O_RDWR, 0)
+		info, _ := file.Stat()
+		file.WriteAt([]byte{'X'}, info.Size()-1)
+		file.Close()
+
+		store = openLogStore(t, path, LogStoreOptions{})
+		defer store.Close()
+		assertStoreContents(t, store, Dictionary{"a": "first"})
+	})
+
+	t.Run("compaction keeps only live words", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "words.log")
+		store := openLogStore(t, path, LogStoreOptions{CompactRatio: -1})
+		for i := 0; i < 100; i++ {
+			store.Store("word", "definition "+strconv.Itoa(i))
+		}
+		store.Store("gone", "removed")
+		store.Remove("gone")
+		before, _ := os.Stat(path)
+
+		assertError(t, store.Compact(), nil)
+
+		after, _ := os.Stat(path)
+		if after.Size() >= before.Size() {
+			t.Errorf("expected compaction to shrink the log, got %d bytes from %d", after.Size(), before.Size())
+		}
+		assertStoreContents(t, store, Dictionary{"word": "definition 99"})
+
+		store.Store("new", "after compaction")
+		store.Close()
+		store = openLogStore(t, path, LogStoreOptions{})
+		defer store.Close()
+		assertStoreContents(t, store, Dictionary{"word": "definition 99", "new": "after compaction"})
+	})
+
+	t.Run("compacts in the background", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "words.log")
+		store := openLogStore(t, path, LogStoreOptions{MinCompactSize: 1024})
+		defer store.Close()
+
+		for i := 0; i < 200; i++ {
+			store.Store("word", "definition "+strconv.Itoa(i))
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for {
+			info, _ := os.Stat(path)
+			if info.Size() < 1024 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("log was not compacted, still %d bytes", info.Size())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		assertStoreContents(t, store, Dictionary{"word": "definition 199"})
+	})
+
+	t.Run("keeps serving while compacting", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "words.log")
+		store := openLogStore(t, path, LogStoreOptions{CompactRatio: -1})
+		for i := 0; i < 10; i++ {
+			store.Store("word", "definition "+strconv.Itoa(i))
+		}
+		store.Store("gone", "removed soon")
+		store.copied = func() {
+			store.Store("new", "during compaction")
+			store.Store("word", "changed during compaction")
+			store.Remove("gone")
+			if definition, _, _ := store.Load("new"); definition != "during compaction" {
+				t.Errorf("got %q want the new definition", definition)
+			}
+		}
+
+		assertError(t, store.Compact(), nil)
+
+		want := Dictionary{"new": "during compaction", "word": "changed during compaction"}
+		assertStoreContents(t, store, want)
+		store.Close()
+		store = openLogStore(t, path, LogStoreOptions{})
+		defer store.Close()
+		assertStoreContents(t, store, want)
+	})
+
+	t.Run("reports background compaction errors", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "words")
+		os.Mkdir(dir, 0755)
+		errs := make(chan error, 1)
+		store := openLogStore(t, filepath.Join(dir, "words.log"), LogStoreOptions{
+			MinCompactSize: 1024,
+			OnCompactError: func(err error) {
+				select {
+				case errs <- err:
+				default:
+				}
+			},
+		})
+		// Without its directory the compacted log cannot be created.
+		os.RemoveAll(dir)
+
+		for i := 0; i < 200; i++ {
+			store.Store("word", "definition "+strconv.Itoa(i))
+		}
+
+		select {
+		case err := <-errs:
+			if !os.IsNotExist(err) {
+				t.Errorf("got %v want a missing directory", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("no compaction error was reported")
+		}
+		if err := store.Close(); !os.IsNotExist(err) {
+			t.Errorf("got %v from Close want the compaction error", err)
+		}
+	})
+
+	t.Run("discards an interrupted compaction", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "words.log")
+		store := openLogStore(t, path, LogStoreOptions{})
+		store.Store("a", "first")
+		store.Close()
+		ioutil.WriteFile(compactionPath(path), []byte("partial"), 0644)
+
+		store = openLogStore(t, path, LogStoreOptions{})
+		defer store.Close()
+
+		assertStoreContents(t, store, Dictionary{"a": "first"})
+		if _, err := os.Stat(compactionPath(path)); !os.IsNotExist(err) {
+			t.Error("expected the leftover compaction file to be removed")
+		}
+	})
+
+	t.Run("closed store", func(t *testing.T) {
+		store := openLogStore(t, filepath.Join(t.TempDir(), "words.log"), LogStoreOptions{})
+		store.Close()
+
+		assertError(t, store.Store("a", "first"), ErrStoreClosed)
+		assertError(t, store.Close(), ErrStoreClosed)
+	})
+
+	t.Run("closed by several goroutines at once", func(t *testing.T) {
+		store := openLogStore(t, filepath.Join(t.TempDir(), "words.log"), LogStoreOptions{})
+		store.Store("a", "first")
+
+		const closers = 8
+		errs := make(chan error, closers)
+		for i := 0; i < closers; i++ {
+			go func() { errs <- store.Close() }()
+		}
+
+		var closed int
+		for i := 0; i < closers; i++ {
+			switch err := <-errs; err {
+			case nil:
+				closed++
+			case ErrStoreClosed:
+			default:
+				t.Errorf("unexpected error %v", err)
+			}
+		}
+		if closed != 1 {
+			t.Errorf("got %d successful closes want 1", closed)
+		}
+	})
+}
+
+func openLogStore(t *testing.T, path string, options LogStoreOptions) *LogStore {
+	t.Helper()
+	store, err := OpenLogStore(path, options)
+	if err != nil {
+		t.Fatal("could not open log store:", err)
+	}
+	return store
+}
+
+func assertStoreContents(t *testing.T, store Store, want Dictionary) {
+	t.Helper()
+	got := Dictionary{}
+	if err := store.Range(func(word, definition string) bool {
+		got[word] = definition
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+package maps
+
+import "sort"
+
+const (
+	ErrMergeConflict = DictionaryErr("merge has unresolved conflicts")
+	ErrUnresolved    = DictionaryErr("conflict left unresolved")
+)
+
+type DefinitionChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// DictionaryDiff lists what changed going from one Dictionary to another.
+type DictionaryDiff struct {
+	Added   Dictionary                  `json:"added"`
+	Removed Dictionary                  `json:"removed"`
+	Changed map[string]DefinitionChange `json:"changed"`
+}
+
+func (d DictionaryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares two dictionaries.
+func Diff(from, to Dictionary) DictionaryDiff {
+	diff := DictionaryDiff{Added: Dictionary{}, Removed: Dictionary{}, Changed: map[string]DefinitionChange{}}
+	for word, old := range from {
+		definition, ok := to[word]
+		switch {
+		case !ok:
+			diff.Removed[word] = old
+		case definition != old:
+			diff.Changed[word] = DefinitionChange{Old: old, New: definition}
+		}
+	}
+	for word, definition := range to {
+		if _, ok := from[word]; !ok {
+			diff.Added[word] = definition
+		}
+	}
+	return diff
+}
+
+type Resolution string
+
+const (
+	ResolvedOurs   Resolution = "ours"
+	ResolvedTheirs Resolution = "theirs"
+	ResolvedCustom Resolution = "custom"
+	Unresolved     Resolution = "unresolved"
+)
+
+// Conflict is a word both sides changed differently. A nil definition means
+// the word is missing on that side.
+type Conflict struct {
+	Word       string     `json:"word"`
+	Base       *string    `json:"base"`
+	Ours       *string    `json:"ours"`
+	Theirs     *string    `json:"theirs"`
+	Resolution Resolution `json:"resolution"`
+	Result     *string    `json:"result"`
+}
+
+type MergeReport struct {
+	Conflicts []Conflict `json:"conflicts"`
+}
+
+// Resolver decides the outcome of a conflict: the definition to keep, or keep
+// false to leave the word out. Returning ErrUnresolved leaves the conflict
+// for a person to look at.
+type Resolver func(c Conflict) (definition string, keep bool, err error)
+
+func KeepOurs(c Conflict) (string, bool, error) {
+	return deref(c.Ours)
+}
+
+func KeepTheirs(c Conflict) (string, bool, error) {
+	return deref(c.Theirs)
+}
+
+// Merge combines two dictionaries edited independently from base. A word
+// changed on only one side takes that change; words changed differently on
+// both sides are passed to resolve. Unresolved conflicts keep our side and
+// make Merge return ErrMergeConflict alongside the result and report.
+func Merge(base, ours, theirs Dictionary, resolve Resolver) (Dictionary, MergeReport, error) {
+	merged := Dictionary{}
+	report := MergeReport{Conflicts: []Conflict{}}
+
+	for _, word := range allWords(base, ours, theirs) {
+		b, o, t := lookup(base, word), lookup(ours, word), lookup(theirs, word)
+
+		var result *string
+		switch {
+		case sameDefinition(o, t), sameDefinition(t, b):
+			result = o
+		case sameDefinition(o, b):
+			result = t
+		default:
+			conflict := Conflict{Word: word, Base: b, Ours: o, Theirs: t, Resolution: Unresolved}
+			result = o
+			if resolve != nil {
+				definition, keep, err := resolve(conflict)
+				switch {
+				case err == ErrUnresolved:
+				case err != nil:
+					return nil, report, err
+				case keep:
+					result = &definition
+					conflict.Resolution = resolutionOf(result, o, t)
+				default:
+					result = nil
+					conflict.Resolution = resolutionOf(result, o, t)
+				}
+			}
+			conflict.Result = result
+			report.Conflicts = append(report.Conflicts, conflict)
+		}
+
+		if result != nil {
+			merged[word] = *result
+		}
+	}
+
+	if report.Unresolved() > 0 {
+		return merged, report, ErrMergeConflict
+	}
+	return merged, report, nil
+}
+
+// Unresolved counts the conflicts no resolver settled.
+func (r MergeReport) Unresolved() int {
+	n := 0
+	for _, c := range r.Conflicts {
+		if c.Resolution == Unresolved {
+			n++
+		}
+	}
+	return n
+}
+
+func resolutionOf(result, ours, theirs *string) Resolution {
+	switch {
+	case sameDefinition(result, ours):
+		return ResolvedOurs
+	case sameDefinition(result, theirs):
+		return ResolvedTheirs
+	default:
+		return ResolvedCustom
+	}
+}
+
+func allWords(dictionaries ...Dictionary) []string {
+	seen := map[string]bool{}
+	var words []string
+	for _, d := range dictionaries {
+		for word := range d {
+			if !seen[word] {
+				seen[word] = true
+				words = append(words, word)
+			}
+		}
+	}
+	sort.Strings(words)
+	return words
+}
+
+func lookup(d Dictionary, word string) *string {
+	definition, ok := d[word]
+	if !ok {
+		return nil
+	}
+	return &definition
+}
+
+func sameDefinition(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func deref(definition *string) (string, bool, error) {
+	if definition == nil {
+		return "", false, nil
+	}
+	return *definition, true, nil
+}
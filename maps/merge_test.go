@@ -0,0 +1,103 @@
+package maps
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	from := Dictionary{"kept": "same", "changed": "old", "removed": "gone"}
+	to := Dictionary{"kept": "same", "changed": "new", "added": "fresh"}
+
+	got := Diff(from, to)
+
+	want := DictionaryDiff{
+		Added:   Dictionary{"added": "fresh"},
+		Removed: Dictionary{"removed": "gone"},
+		Changed: map[string]DefinitionChange{"changed": {Old: "old", New: "new"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+	if !Diff(to, to).Empty() {
+		t.Error("expected no differences between a dictionary and itself")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := Dictionary{"a": "base", "b": "base", "c": "base", "d": "base"}
+	ours := Dictionary{"a": "ours", "b": "base", "c": "ours", "e": "ours"}
+	theirs := Dictionary{"a": "base", "b": "theirs", "c": "theirs", "d": "base", "f": "theirs"}
+
+	t.Run("takes one-sided changes", func(t *testing.T) {
+		merged, report, err := Merge(base, ours, theirs, KeepOurs)
+
+		assertError(t, err, nil)
+		want := Dictionary{"a": "ours", "b": "theirs", "c": "ours", "e": "ours", "f": "theirs"}
+		if !reflect.DeepEqual(merged, want) {
+			t.Errorf("got %v want %v", merged, want)
+		}
+		if len(report.Conflicts) != 1 || report.Conflicts[0].Resolution != ResolvedOurs {
+			t.Errorf("unexpected report %+v", report)
+		}
+	})
+
+	t.Run("theirs", func(t *testing.T) {
+		merged, _, err := Merge(base, ours, theirs, KeepTheirs)
+
+		assertError(t, err, nil)
+		assertStrings(t, merged["c"], "theirs")
+	})
+
+	t.Run("callback", func(t *testing.T) {
+		merged, report, err := Merge(base, ours, theirs, func(c Conflict) (string, bool, error) {
+			return *c.Ours + " and " + *c.Theirs, true, nil
+		})
+
+		assertError(t, err, nil)
+		assertStrings(t, merged["c"], "ours and theirs")
+		if report.Conflicts[0].Resolution != ResolvedCustom {
+			t.Errorf("got resolution %q want %q", report.Conflicts[0].Resolution, ResolvedCustom)
+		}
+	})
+
+	t.Run("delete against edit", func(t *testing.T) {
+		merged, report, err := Merge(Dictionary{"a": "base"}, Dictionary{}, Dictionary{"a": "theirs"}, KeepOurs)
+
+		assertError(t, err, nil)
+		if _, ok := merged["a"]; ok {
+			t.Error("expected our delete to win")
+		}
+		if c := report.Conflicts[0]; c.Ours != nil || c.Result != nil || *c.Theirs != "theirs" {
+			t.Errorf("unexpected conflict %+v", c)
+		}
+	})
+
+	t.Run("unresolved conflicts", func(t *testing.T) {
+		merged, report, err := Merge(base, ours, theirs, nil)
+
+		assertError(t, err, ErrMergeConflict)
+		assertStrings(t, merged["c"], "ours")
+		if report.Unresolved() != 1 {
+			t.Errorf("got %d unresolved want 1", report.Unresolved())
+		}
+
+		data, _ := json.Marshal(report)
+		want := `{"conflicts":[{"word":"c","base":"base","ours":"ours","theirs":"theirs","resolution":"unresolved","result":"ours"}]}`
+		assertStrings(t, string(data), want)
+	})
+
+	t.Run("resolver errors abort the merge", func(t *testing.T) {
+		failure := DictionaryErr("resolver failed")
+
+		_, _, err := Merge(base, ours, theirs, func(c Conflict) (string, bool, error) {
+			return "", false, failure
+		})
+
+		if err == nil || !strings.Contains(err.Error(), "resolver failed") {
+			t.Errorf("got %v want %v", err, failure)
+		}
+	})
+}
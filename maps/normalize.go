@@ -0,0 +1,200 @@
+package maps
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+type NormalizationForm int
+
+const (
+	NoNormalization NormalizationForm = iota
+	NFC
+	NFKC
+)
+
+// KeyPolicy decides which spellings of a word are treated as the same key.
+type KeyPolicy struct {
+	// Form applies Unicode normalization. NFKC also folds compatibility
+	// characters, so "ｒｕｎ" becomes "run".
+	Form NormalizationForm
+	// FoldCase makes "Run" and "run" the same key.
+	FoldCase bool
+	// TrimSpace removes leading and trailing whitespace and collapses inner runs
+	// of whitespace to a single space.
+	TrimSpace bool
+	// Stem strips common English suffixes, so "runs" and "run" are the same key.
+	Stem bool
+}
+
+// DefaultKeyPolicy treats words differing only in case, width, composition or
+// surrounding whitespace as the same key.
+var DefaultKeyPolicy = KeyPolicy{Form: NFKC, FoldCase: true, TrimSpace: true}
+
+var caseFolder = cases.Fold()
+
+// Key returns the normalized form of word.
+func (p KeyPolicy) Key(word string) string {
+	if p.TrimSpace {
+		word = strings.Join(strings.Fields(word), " ")
+	}
+	switch p.Form {
+	case NFC:
+		word = norm.NFC.String(word)
+	case NFKC:
+		word = norm.NFKC.String(word)
+	}
+	if p.FoldCase {
+		word = caseFolder.String(word)
+	}
+	if p.Stem {
+		fields := strings.Split(word, " ")
+		for i, field := range fields {
+			fields[i] = stem(field)
+		}
+		word = strings.Join(fields, " ")
+	}
+	return word
+}
+
+// stemSuffixes are tried in order; the first that leaves a long enough stem is
+// replaced. Verb endings can follow a doubled consonant, as in "running",
+// which is then undoubled.
+var stemSuffixes = []struct {
+	suffix, replacement string
+	undouble            bool
+}{
+	{"ies", "y", false},
+	{"ing", "", true},
+	{"ed", "", true},
+	{"ly", "", false},
+	{"es", "", false},
+	{"s", "", false},
+}
+
+const minStemLength = 3
+
+// stem is a deliberately simple suffix stripper, not a full Porter stemmer.
+func stem(word string) string {
+	if strings.HasSuffix(word, "ss") {
+		return word
+	}
+	for _, s := range stemSuffixes {
+		if strings.HasSuffix(word, s.suffix) {
+			stemmed := strings.TrimSuffix(word, s.suffix) + s.replacement
+			if len([]rune(stemmed)) >= minStemLength {
+				if s.undouble {
+					stemmed = undouble(stemmed)
+				}
+				return stemmed
+			}
+		}
+	}
+	return word
+}
+
+// undouble drops the last letter of a stem ending in a doubled consonant,
+// except l, s and z, which are usually doubled anyway as in "fall" or "miss".
+func undouble(stem string) string {
+	runes := []rune(stem)
+	n := len(runes)
+	if n <= minStemLength {
+		return stem
+	}
+	last := runes[n-1]
+	if last != runes[n-2] || strings.ContainsRune("aeiouylsz", last) {
+		return stem
+	}
+	return string(runes[:n-1])
+}
+
+type normalizedEntry struct {
+	display    string
+	definition string
+}
+
+// NormalizedDictionary looks words up by their normalized key while keeping
+// the form they were first added with for display.
+type NormalizedDictionary struct {
+	policy  KeyPolicy
+	entries map[string]normalizedEntry
+}
+
+func NewNormalizedDictionary(policy KeyPolicy) *NormalizedDictionary {
+	return &NormalizedDictionary{policy: policy, entries: map[string]normalizedEntry{}}
+}
+
+func (d *NormalizedDictionary) Search(word string) (string, error) {
+	e, ok := d.entries[d.policy.Key(word)]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return e.definition, nil
+}
+
+// Display returns the form word was added with.
+func (d *NormalizedDictionary) Display(word string) (string, error) {
+	e, ok := d.entries[d.policy.Key(word)]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return e.display, nil
+}
+
+// Add fails with ErrWordExists if word normalizes to the key of a word already
+// in the dictionary.
+func (d *NormalizedDictionary) Add(word, definition string) error {
+	key := d.policy.Key(word)
+	if _, ok := d.entries[key]; ok {
+		return ErrWordExists
+	}
+	d.entries[key] = normalizedEntry{display: word, definition: definition}
+	return nil
+}
+
+func (d *NormalizedDictionary) Update(word, definition string) error {
+	key := d.policy.Key(word)
+	e, ok := d.entries[key]
+	if !ok {
+		return ErrWordDoesNotExist
+	}
+	e.definition = definition
+	d.entries[key] = e
+	return nil
+}
+
+func (d *NormalizedDictionary) Delete(word string) error {
+	key := d.policy.Key(word)
+	if _, ok := d.entries[key]; !ok {
+		return ErrNotFound
+	}
+	delete(d.entries, key)
+	return nil
+}
+
+// Dictionary returns the words in their display form.
+func (d *NormalizedDictionary) Dictionary() Dictionary {
+	dictionary := make(Dictionary, len(d.entries))
+	for _, e := range d.entries {
+		dictionary[e.display] = e.definition
+	}
+	return dictionary
+}
+
+// Words returns the display forms in key order.
+func (d *NormalizedDictionary) Words() []string {
+	keys := make([]string, 0, len(d.entries))
+	for key := range d.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	words := make([]string, len(keys))
+	for i, key := range keys {
+		words[i] = d.entries[key].display
+	}
+	return words
+}
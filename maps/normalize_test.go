@@ -0,0 +1,84 @@
+package maps
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyPolicy(t *testing.T) {
+	cases := []struct {
+		policy KeyPolicy
+		word   string
+		want   string
+	}{
+		{KeyPolicy{}, " Run ", " Run "},
+		{KeyPolicy{FoldCase: true}, "Run", "run"},
+		{KeyPolicy{FoldCase: true}, "STRASSE", "strasse"},
+		{KeyPolicy{Form: NFKC}, "ｒｕｎ", "run"},
+		{KeyPolicy{Form: NFC}, "cafe\u0301", "caf\u00e9"},
+		{KeyPolicy{Form: NFC}, "ｒｕｎ", "ｒｕｎ"},
+		{KeyPolicy{TrimSpace: true}, "  ice \t cream ", "ice cream"},
+		{KeyPolicy{Stem: true}, "running", "run"},
+		{KeyPolicy{Stem: true}, "stopped", "stop"},
+		{KeyPolicy{Stem: true}, "falling", "fall"},
+		{KeyPolicy{Stem: true}, "missed", "miss"},
+		{KeyPolicy{Stem: true}, "adding", "add"},
+		{KeyPolicy{Stem: true}, "berries", "berry"},
+		{KeyPolicy{Stem: true}, "glass", "glass"},
+		{KeyPolicy{Stem: true}, "is", "is"},
+		{DefaultKeyPolicy, " ＲＵＮ ", "run"},
+	}
+
+	for _, c := range cases {
+		got := c.policy.Key(c.word)
+		if got != c.want {
+			t.Errorf("%+v.Key(%q) got %q want %q", c.policy, c.word, got, c.want)
+		}
+	}
+}
+
+func TestNormalizedDictionary(t *testing.T) {
+	t.Run("finds any spelling", func(t *testing.T) {
+		dictionary := NewNormalizedDictionary(DefaultKeyPolicy)
+		dictionary.Add("Run", "to move quickly")
+
+		for _, word := range []string{"Run", "run", "ｒｕｎ", " RUN "} {
+			got, err := dictionary.Search(word)
+			assertError(t, err, nil)
+			assertStrings(t, got, "to move quickly")
+		}
+	})
+
+	t.Run("keeps the display form", func(t *testing.T) {
+		dictionary := NewNormalizedDictionary(DefaultKeyPolicy)
+		dictionary.Add("Run", "to move quickly")
+		dictionary.Update("run", "to go fast")
+
+		got, _ := dictionary.Display("ｒｕｎ")
+		assertStrings(t, got, "Run")
+		want := Dictionary{"Run": "to go fast"}
+		if !reflect.DeepEqual(dictionary.Dictionary(), want) {
+			t.Errorf("got %v want %v", dictionary.Dictionary(), want)
+		}
+	})
+
+	t.Run("detects collisions after normalization", func(t *testing.T) {
+		dictionary := NewNormalizedDictionary(DefaultKeyPolicy)
+		dictionary.Add("Run", "to move quickly")
+
+		err := dictionary.Add("ｒｕｎ", "duplicate")
+
+		assertError(t, err, ErrWordExists)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		dictionary := NewNormalizedDictionary(DefaultKeyPolicy)
+		dictionary.Add("Run", "to move quickly")
+
+		assertError(t, dictionary.Delete("RUN"), nil)
+		assertError(t, dictionary.Delete("RUN"), ErrNotFound)
+		if len(dictionary.Words()) != 0 {
+			t.Errorf("expected no words, got %v", dictionary.Words())
+		}
+	})
+}
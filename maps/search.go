@@ -0,0 +1,266 @@
+package maps
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	ErrEmptyQuery        = DictionaryErr("query has no searchable terms")
+	ErrUnterminatedQuote = DictionaryErr("query has an unterminated phrase quote")
+)
+
+// BM25 tuning parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "was": true, "with": true,
+}
+
+// Index wraps a Dictionary with an inverted index over its definitions.
+type Index struct {
+	dictionary Dictionary
+	// postings maps a term to the positions it appears at in each word's definition.
+	postings    map[string]map[string][]int
+	lengths     map[string]int
+	totalLength int
+}
+
+type Match struct {
+	Word  string
+	Score float64
+}
+
+func NewIndex(dictionary Dictionary) *Index {
+	if dictionary == nil {
+		dictionary = Dictionary{}
+	}
+	i := &Index{dictionary: dictionary, postings: map[string]map[string][]int{}, lengths: map[string]int{}}
+	for word, definition := range dictionary {
+		i.index(word, definition)
+	}
+	return i
+}
+
+func (i *Index) Search(word string) (string, error) {
+	return i.dictionary.Search(word)
+}
+
+func (i *Index) Add(word, definition string) error {
+	if err := i.dictionary.Add(word, definition); err != nil {
+		return err
+	}
+	i.index(word, definition)
+	return nil
+}
+
+func (i *Index) Update(word, definition string) error {
+	old, _ := i.dictionary.Search(word)
+	if err := i.dictionary.Update(word, definition); err != nil {
+		return err
+	}
+	i.unindex(word, old)
+	i.index(word, definition)
+	return nil
+}
+
+func (i *Index) Delete(word string) error {
+	old, err := i.dictionary.Search(word)
+	if err != nil {
+		return err
+	}
+	i.dictionary.Delete(word)
+	i.unindex(word, old)
+	return nil
+}
+
+// Query finds the words whose definitions match query, best match first.
+// Space separated terms must all match, OR separates alternatives, and double
+// quotes match a phrase.
+//
+//	network "packet switching" OR router
+func (i *Index) Query(query string) ([]Match, error) {
+	alternatives, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := map[string]float64{}
+	for _, clauses := range alternatives {
+		for word, score := range i.matchAll(clauses) {
+			if score > scores[word] {
+				scores[word] = score
+			}
+		}
+	}
+
+	matches := make([]Match, 0, len(scores))
+	for word, score := range scores {
+		matches = append(matches, Match{word, score})
+	}
+	sort.Slice(matches, func(a, b int) bool {
+		if matches[a].Score != matches[b].Score {
+			return matches[a].Score > matches[b].Score
+		}
+		return matches[a].Word < matches[b].Word
+	})
+	return matches, nil
+}
+
+// matchAll scores the words matching every clause.
+func (i *Index) matchAll(clauses [][]token) map[string]float64 {
+	var candidates map[string]float64
+	for _, phrase := range clauses {
+		matched := map[string]float64{}
+		for word := range i.postings[phrase[0].term] {
+			if candidates != nil {
+				if _, ok := candidates[word]; !ok {
+					continue
+				}
+			}
+			if i.hasPhrase(word, phrase) {
+				matched[word] = candidates[word] + i.score(word, phrase)
+			}
+		}
+		candidates = matched
+		if len(candidates) == 0 {
+			break
+		}
+	}
+	return candidates
+}
+
+func (i *Index) hasPhrase(word string, phrase []token) bool {
+	for _, start := range i.postings[phrase[0].term][word] {
+		found := true
+		for _, t := range phrase[1:] {
+			if !containsInt(i.postings[t.term][word], start+t.position-phrase[0].position) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Index) score(word string, phrase []token) float64 {
+	documents := float64(len(i.lengths))
+	averageLength := float64(i.totalLength) / documents
+	length := float64(i.lengths[word])
+
+	var score float64
+	for _, t := range phrase {
+		postings := i.postings[t.term]
+		frequency := float64(len(postings[word]))
+		withTerm := float64(len(postings))
+		idf := math.Log(1 + (documents-withTerm+0.5)/(withTerm+0.5))
+		score += idf * frequency * (bm25K1 + 1) / (frequency + bm25K1*(1-bm25B+bm25B*length/averageLength))
+	}
+	return score
+}
+
+func (i *Index) index(word, definition string) {
+	tokens := tokenize(definition)
+	for _, t := range tokens {
+		if i.postings[t.term] == nil {
+			i.postings[t.term] = map[string][]int{}
+		}
+		i.postings[t.term][word] = append(i.postings[t.term][word], t.position)
+	}
+	i.lengths[word] = len(tokens)
+	i.totalLength += len(tokens)
+}
+
+func (i *Index) unindex(word, definition string) {
+	for _, t := range tokenize(definition) {
+		delete(i.postings[t.term], word)
+		if len(i.postings[t.term]) == 0 {
+			delete(i.postings, t.term)
+		}
+	}
+	i.totalLength -= i.lengths[word]
+	delete(i.lengths, word)
+}
+
+type token struct {
+	term     string
+	position int
+}
+
+// tokenize lower-cases text and splits it into terms, dropping stop words but
+// keeping their positions so phrases still line up.
+func tokenize(text string) []token {
+	var tokens []token
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for position, field := range fields {
+		if !stopWords[field] {
+			tokens = append(tokens, token{field, position})
+		}
+	}
+	return tokens
+}
+
+// parseQuery returns the OR separated alternatives of query, each a list of
+// phrases that must all match. A single term is a phrase of one token.
+func parseQuery(query string) ([][][]token, error) {
+	if strings.Count(query, `"`)%2 != 0 {
+		return nil, ErrUnterminatedQuote
+	}
+
+	var alternatives [][][]token
+	var clauses [][]token
+	flush := func() {
+		if len(clauses) > 0 {
+			alternatives = append(alternatives, clauses)
+		}
+		clauses = nil
+	}
+
+	for n, part := range strings.Split(query, `"`) {
+		if n%2 == 1 {
+			if phrase := tokenize(part); len(phrase) > 0 {
+				clauses = append(clauses, phrase)
+			}
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			switch field {
+			case "OR":
+				flush()
+			case "AND":
+			default:
+				for _, t := range tokenize(field) {
+					clauses = append(clauses, []token{t})
+				}
+			}
+		}
+	}
+	flush()
+
+	if len(alternatives) == 0 {
+		return nil, ErrEmptyQuery
+	}
+	return alternatives, nil
+}
+
+func containsInt(values []int, want int) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package maps
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestIndexQuery(t *testing.T) {
+	dictionary := Dictionary{
+		"router":   "a device that forwards packets between computer networks",
+		"internet": "a global network of networks using packet switching",
+		"modem":    "a device that converts signals for a network connection",
+		"apple":    "a round fruit",
+	}
+
+	cases := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"single term", "fruit", []string{"apple"}},
+		{"terms are combined with AND", "device network", []string{"modem"}},
+		{"explicit AND", "device AND network", []string{"modem"}},
+		{"OR", "fruit OR switching", []string{"apple", "internet"}},
+		{"phrase", `"packet switching"`, []string{"internet"}},
+		{"phrase order matters", `"switching packet"`, nil},
+		{"phrase across stop words", `"network of networks"`, []string{"internet"}},
+		{"case insensitive", "FRUIT", []string{"apple"}},
+	}
+
+	index := NewIndex(dictionary)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			matches, err := index.Query(c.query)
+			assertError(t, err, nil)
+			assertMatchedWords(t, matches, c.want)
+		})
+	}
+
+	t.Run("ranks more frequent terms higher", func(t *testing.T) {
+		index := NewIndex(Dictionary{
+			"a": "network cable cable",
+			"b": "network network cable",
+			"c": "cable",
+		})
+
+		matches, _ := index.Query("network")
+
+		if len(matches) != 2 || matches[0].Word != "b" {
+			t.Errorf("expected b to rank first, got %v", matches)
+		}
+	})
+
+	t.Run("invalid queries", func(t *testing.T) {
+		_, err := index.Query("the of")
+		assertError(t, err, ErrEmptyQuery)
+
+		_, err = index.Query(`"packet switching`)
+		assertError(t, err, ErrUnterminatedQuote)
+	})
+}
+
+func TestIndexStaysConsistent(t *testing.T) {
+	index := NewIndex(Dictionary{})
+
+	index.Add("test", "an old definition")
+	index.Update("test", "a new definition")
+
+	matches, _ := index.Query("old")
+	assertMatchedWords(t, matches, nil)
+	matches, _ = index.Query("new")
+	assertMatchedWords(t, matches, []string{"test"})
+
+	index.Delete("test")
+
+	matches, _ = index.Query("new")
+	assertMatchedWords(t, matches, nil)
+	if len(index.postings) != 0 || index.totalLength != 0 {
+		t.Errorf("expected an empty index, got %v", index.postings)
+	}
+}
+
+func assertMatchedWords(t *testing.T, matches []Match, want []string) {
+	t.Helper()
+	var got []string
+	for _, m := range matches {
+		got = append(got, m.Word)
+	}
+	// Ranking is covered separately; compare the matched set.
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,243 @@
+package maps
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	wordsPath       = "/words"
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// DictionaryServer exposes a Dictionary over HTTP.
+type DictionaryServer struct {
+	mu         sync.RWMutex
+	dictionary Dictionary
+}
+
+type entry struct {
+	Word       string `json:"word"`
+	Definition string `json:"definition"`
+}
+
+type page struct {
+	Words  []entry `json:"words"`
+	Total  int     `json:"total"`
+	Offset int     `json:"offset"`
+	Limit  int     `json:"limit"`
+	Next   string  `json:"next,omitempty"`
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func NewDictionaryServer(dictionary Dictionary) *DictionaryServer {
+	if dictionary == nil {
+		dictionary = Dictionary{}
+	}
+	return &DictionaryServer{dictionary: dictionary}
+}
+
+func (s *DictionaryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == wordsPath || r.URL.Path == wordsPath+"/" {
+		s.list(w, r)
+		return
+	}
+
+	word := strings.TrimPrefix(r.URL.Path, wordsPath+"/")
+	if word == r.URL.Path || word == "" || strings.Contains(word, "/") {
+		writeError(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		s.get(w, r, word)
+	case http.MethodPut:
+		s.put(w, r, word)
+	case http.MethodDelete:
+		s.delete(w, r, word)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, DictionaryErr("method not allowed"))
+	}
+}
+
+func (s *DictionaryServer) list(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, DictionaryErr("method not allowed"))
+		return
+	}
+
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, DictionaryErr("offset must be a non-negative integer"))
+		return
+	}
+	limit, err := queryInt(r, "limit", defaultPageSize)
+	if err != nil || limit < 1 || limit > maxPageSize {
+		writeError(w, http.StatusBadRequest, DictionaryErr("limit must be between 1 and "+strconv.Itoa(maxPageSize)))
+		return
+	}
+
+	s.mu.RLock()
+	words := make([]string, 0, len(s.dictionary))
+	for word := range s.dictionary {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	p := page{Words: []entry{}, Total: len(words), Offset: offset, Limit: limit}
+	for i := offset; i < len(words) && i-offset < limit; i++ {
+		p.Words = append(p.Words, entry{Word: words[i], Definition: s.dictionary[words[i]]})
+	}
+	s.mu.RUnlock()
+
+	// offset+limit could overflow for huge offsets.
+	if offset < p.Total-limit {
+		p.Next = wordsPath + "?offset=" + strconv.Itoa(offset+limit) + "&limit=" + strconv.Itoa(limit)
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *DictionaryServer) get(w http.ResponseWriter, r *http.Request, word string) {
+	s.mu.RLock()
+	definition, err := s.dictionary.Search(word)
+	s.mu.RUnlock()
+
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	tag := etag(definition)
+	w.Header().Set("ETag", tag)
+	if matchesETag(r.Header.Get("If-None-Match"), tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry{Word: word, Definition: definition})
+}
+
+func (s *DictionaryServer) put(w http.ResponseWriter, r *http.Request, word string) {
+	var body struct {
+		Definition *string `json:"definition"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Definition == nil {
+		writeError(w, http.StatusBadRequest, DictionaryErr("request body must be a JSON object with a definition"))
+		return
+	}
+	definition := *body.Definition
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, searchErr := s.dictionary.Search(word)
+	ifMatch := r.Header.Get("If-Match")
+
+	var err error
+	status := http.StatusOK
+	switch {
+	case r.Header.Get("If-None-Match") == "*":
+		err = s.dictionary.Add(word, definition)
+		status = http.StatusCreated
+	case ifMatch != "":
+		// If-Match, even "*", cannot match a word that does not exist.
+		if searchErr == ErrNotFound {
+			writeError(w, http.StatusPreconditionFailed, ErrWordDoesNotExist)
+			return
+		}
+		if searchErr == nil && !matchesETag(ifMatch, etag(current)) {
+			writeError(w, http.StatusPreconditionFailed, DictionaryErr("definition has been modified"))
+			return
+		}
+		err = s.dictionary.Update(word, definition)
+	case searchErr == ErrNotFound:
+		err = s.dictionary.Add(word, definition)
+		status = http.StatusCreated
+	default:
+		err = s.dictionary.Update(word, definition)
+	}
+
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(definition))
+	writeJSON(w, status, entry{Word: word, Definition: definition})
+}
+
+func (s *DictionaryServer) delete(w http.ResponseWriter, r *http.Request, word string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.dictionary.Search(word)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && !matchesETag(ifMatch, etag(current)) {
+		writeError(w, http.StatusPreconditionFailed, DictionaryErr("definition has been modified"))
+		return
+	}
+
+	s.dictionary.Delete(word)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func statusFor(err error) int {
+	switch err {
+	case ErrNotFound, ErrWordDoesNotExist:
+		return http.StatusNotFound
+	case ErrWordExists:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func etag(definition string) string {
+	sum := sha256.Sum256([]byte(definition))
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// matchesETag reports whether tag is listed in an If-Match or If-None-Match header.
+func matchesETag(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func queryInt(r *http.Request, key string, fallback int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorBody{Error: err.Error()})
+}
@@ -0,0 +1,236 @@
+package maps
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDictionaryServerGet(t *testing.T) {
+	server := NewDictionaryServer(Dictionary{"test": "this is just a test"})
+
+	t.Run("known word", func(t *testing.T) {
+		response := serve(server, http.MethodGet, "/words/test", "", nil)
+
+		assertStatus(t, response, http.StatusOK)
+		var got entry
+		decodeBody(t, response, &got)
+		assertStrings(t, got.Definition, "this is just a test")
+		if response.Header().Get("ETag") == "" {
+			t.Error("expected an ETag header")
+		}
+	})
+
+	t.Run("unknown word", func(t *testing.T) {
+		response := serve(server, http.MethodGet, "/words/unknown", "", nil)
+
+		assertStatus(t, response, http.StatusNotFound)
+		assertErrorBody(t, response, ErrNotFound)
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		tag := serve(server, http.MethodGet, "/words/test", "", nil).Header().Get("ETag")
+		response := serve(server, http.MethodGet, "/words/test", "", map[string]string{"If-None-Match": tag})
+
+		assertStatus(t, response, http.StatusNotModified)
+	})
+}
+
+func TestDictionaryServerPut(t *testing.T) {
+	t.Run("new word", func(t *testing.T) {
+		dictionary := Dictionary{}
+		server := NewDictionaryServer(dictionary)
+
+		response := serve(server, http.MethodPut, "/words/test", `{"definition":"this is just a test"}`, nil)
+
+		assertStatus(t, response, http.StatusCreated)
+		assertDefinition(t, dictionary, "test", "this is just a test")
+	})
+
+	t.Run("existing word", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+		server := NewDictionaryServer(dictionary)
+
+		response := serve(server, http.MethodPut, "/words/test", `{"definition":"new definition"}`, nil)
+
+		assertStatus(t, response, http.StatusOK)
+		assertDefinition(t, dictionary, "test", "new definition")
+	})
+
+	t.Run("create only on existing word", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+		server := NewDictionaryServer(dictionary)
+
+		response := serve(server, http.MethodPut, "/words/test", `{"definition":"new definition"}`, map[string]string{"If-None-Match": "*"})
+
+		assertStatus(t, response, http.StatusConflict)
+		assertErrorBody(t, response, ErrWordExists)
+		assertDefinition(t, dictionary, "test", "this is just a test")
+	})
+
+	t.Run("conditional update", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+		server := NewDictionaryServer(dictionary)
+		tag := serve(server, http.MethodGet, "/words/test", "", nil).Header().Get("ETag")
+
+		first := serve(server, http.MethodPut, "/words/test", `{"definition":"first"}`, map[string]string{"If-Match": tag})
+		second := serve(server, http.MethodPut, "/words/test", `{"definition":"second"}`, map[string]string{"If-Match": tag})
+
+		assertStatus(t, first, http.StatusOK)
+		assertStatus(t, second, http.StatusPreconditionFailed)
+		assertDefinition(t, dictionary, "test", "first")
+	})
+
+	t.Run("conditional update of unknown word", func(t *testing.T) {
+		server := NewDictionaryServer(Dictionary{})
+
+		for _, ifMatch := range []string{`"abc"`, "*"} {
+			response := serve(server, http.MethodPut, "/words/test", `{"definition":"new"}`, map[string]string{"If-Match": ifMatch})
+
+			assertStatus(t, response, http.StatusPreconditionFailed)
+			assertErrorBody(t, response, ErrWordDoesNotExist)
+		}
+		if _, err := server.dictionary.Search("test"); err != ErrNotFound {
+			t.Errorf("got %v want the word to stay missing", err)
+		}
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		server := NewDictionaryServer(Dictionary{})
+
+		response := serve(server, http.MethodPut, "/words/test", `not json`, nil)
+
+		assertStatus(t, response, http.StatusBadRequest)
+	})
+}
+
+func TestDictionaryServerDelete(t *testing.T) {
+	t.Run("existing word", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+		server := NewDictionaryServer(dictionary)
+
+		response := serve(server, http.MethodDelete, "/words/test", "", nil)
+
+		assertStatus(t, response, http.StatusNoContent)
+		if _, err := dictionary.Search("test"); err != ErrNotFound {
+			t.Error("expected word to be deleted")
+		}
+	})
+
+	t.Run("unknown word", func(t *testing.T) {
+		server := NewDictionaryServer(Dictionary{})
+
+		response := serve(server, http.MethodDelete, "/words/test", "", nil)
+
+		assertStatus(t, response, http.StatusNotFound)
+		assertErrorBody(t, response, ErrNotFound)
+	})
+
+	t.Run("stale etag", func(t *testing.T) {
+		dictionary := Dictionary{"test": "this is just a test"}
+		server := NewDictionaryServer(dictionary)
+
+		response := serve(server, http.MethodDelete, "/words/test", "", map[string]string{"If-Match": `"stale"`})
+
+		assertStatus(t, response, http.StatusPreconditionFailed)
+		assertDefinition(t, dictionary, "test", "this is just a test")
+	})
+}
+
+func TestDictionaryServerList(t *testing.T) {
+	server := NewDictionaryServer(Dictionary{"a": "1", "b": "2", "c": "3"})
+
+	t.Run("first page", func(t *testing.T) {
+		response := serve(server, http.MethodGet, "/words?limit=2", "", nil)
+
+		assertStatus(t, response, http.StatusOK)
+		var got page
+		decodeBody(t, response, &got)
+		if got.Total != 3 || len(got.Words) != 2 || got.Words[0].Word != "a" || got.Words[1].Word != "b" {
+			t.Errorf("unexpected page %+v", got)
+		}
+		assertStrings(t, got.Next, "/words?offset=2&limit=2")
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		response := serve(server, http.MethodGet, "/words?offset=2&limit=2", "", nil)
+
+		var got page
+		decodeBody(t, response, &got)
+		if len(got.Words) != 1 || got.Words[0].Word != "c" || got.Next != "" {
+			t.Errorf("unexpected page %+v", got)
+		}
+	})
+
+	t.Run("huge offset", func(t *testing.T) {
+		maxInt := int(^uint(0) >> 1)
+		for _, offset := range []int{maxInt, maxInt - 1} {
+			response := serve(server, http.MethodGet, "/words?limit=2&offset="+strconv.Itoa(offset), "", nil)
+
+			assertStatus(t, response, http.StatusOK)
+			var got page
+			decodeBody(t, response, &got)
+			if got.Total != 3 || len(got.Words) != 0 || got.Next != "" {
+				t.Errorf("offset %d: unexpected page %+v", offset, got)
+			}
+		}
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		response := serve(server, http.MethodGet, "/words?limit=0", "", nil)
+
+		assertStatus(t, response, http.StatusBadRequest)
+	})
+}
+
+func TestDictionaryServerOverHTTP(t *testing.T) {
+	ts := httptest.NewServer(NewDictionaryServer(Dictionary{"test": "this is just a test"}))
+	defer ts.Close()
+
+	response, err := ts.Client().Get(ts.URL + "/words/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("got status %d want %d", response.StatusCode, http.StatusOK)
+	}
+	if got := response.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("got content type %q", got)
+	}
+}
+
+func serve(handler http.Handler, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+func decodeBody(t *testing.T, response *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.NewDecoder(response.Body).Decode(v); err != nil {
+		t.Fatal("could not decode response body:", err)
+	}
+}
+
+func assertStatus(t *testing.T, response *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if response.Code != want {
+		t.Errorf("got status %d want %d", response.Code, want)
+	}
+}
+
+func assertErrorBody(t *testing.T, response *httptest.ResponseRecorder, want error) {
+	t.Helper()
+	var got errorBody
+	decodeBody(t, response, &got)
+	assertStrings(t, got.Error, want.Error())
+}
@@ -0,0 +1,156 @@
+package maps
+
+import (
+	"sort"
+	"sync"
+)
+
+// Store is where the words of a StoredDictionary live.
+type Store interface {
+	// Load returns the definition of word and whether it is present.
+	Load(word string) (string, bool, error)
+	Store(word, definition string) error
+	Remove(word string) error
+	// Range calls fn for every word in order until fn returns false.
+	Range(fn func(word, definition string) bool) error
+	Close() error
+}
+
+// StoredDictionary offers the Dictionary operations on top of any Store.
+type StoredDictionary struct {
+	mu    sync.Mutex
+	store Store
+}
+
+func NewStoredDictionary(store Store) *StoredDictionary {
+	return &StoredDictionary{store: store}
+}
+
+func (d *StoredDictionary) Search(word string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	definition, ok, err := d.store.Load(word)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", ErrNotFound
+	}
+	return definition, nil
+}
+
+func (d *StoredDictionary) Add(word, definition string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok, err := d.store.Load(word)
+	switch {
+	case err != nil:
+		return err
+	case ok:
+		return ErrWordExists
+	}
+	return d.store.Store(word, definition)
+}
+
+func (d *StoredDictionary) Update(word, definition string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok, err := d.store.Load(word)
+	switch {
+	case err != nil:
+		return err
+	case !ok:
+		return ErrWordDoesNotExist
+	}
+	return d.store.Store(word, definition)
+}
+
+func (d *StoredDictionary) Delete(word string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok, err := d.store.Load(word)
+	switch {
+	case err != nil:
+		return err
+	case !ok:
+		return ErrNotFound
+	}
+	return d.store.Remove(word)
+}
+
+// Dictionary copies every stored word into memory.
+func (d *StoredDictionary) Dictionary() (Dictionary, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dictionary := Dictionary{}
+	err := d.store.Range(func(word, definition string) bool {
+		dictionary[word] = definition
+		return true
+	})
+	return dictionary, err
+}
+
+func (d *StoredDictionary) Close() error {
+	return d.store.Close()
+}
+
+// MemoryStore keeps words in a Dictionary.
+type MemoryStore struct {
+	mu         sync.RWMutex
+	dictionary Dictionary
+}
+
+func NewMemoryStore(dictionary Dictionary) *MemoryStore {
+	if dictionary == nil {
+		dictionary = Dictionary{}
+	}
+	return &MemoryStore{dictionary: dictionary}
+}
+
+func (s *MemoryStore) Load(word string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	definition, ok := s.dictionary[word]
+	return definition, ok, nil
+}
+
+func (s *MemoryStore) Store(word, definition string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dictionary[word] = definition
+	return nil
+}
+
+func (s *MemoryStore) Remove(word string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dictionary.Delete(word)
+	return nil
+}
+
+func (s *MemoryStore) Range(fn func(word, definition string) bool) error {
+	s.mu.RLock()
+	words := make([]string, 0, len(s.dictionary))
+	for word := range s.dictionary {
+		words = append(words, word)
+	}
+	s.mu.RUnlock()
+	sort.Strings(words)
+
+	for _, word := range words {
+		definition, ok, _ := s.Load(word)
+		if ok && !fn(word, definition) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
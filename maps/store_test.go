@@ -0,0 +1,50 @@
+package maps
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStoredDictionary(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store {
+			return NewMemoryStore(Dictionary{})
+		},
+		"log": func(t *testing.T) Store {
+			store, err := OpenLogStore(filepath.Join(t.TempDir(), "words.log"), LogStoreOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return store
+		},
+	}
+
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			dictionary := NewStoredDictionary(open(t))
+			defer dictionary.Close()
+
+			assertError(t, dictionary.Add("test", "this is just a test"), nil)
+			assertError(t, dictionary.Add("test", "duplicate"), ErrWordExists)
+			assertError(t, dictionary.Update("missing", "definition"), ErrWordDoesNotExist)
+			assertError(t, dictionary.Update("test", "new definition"), nil)
+			assertError(t, dictionary.Add("other", "another word"), nil)
+			assertError(t, dictionary.Delete("other"), nil)
+			assertError(t, dictionary.Delete("other"), ErrNotFound)
+
+			got, err := dictionary.Search("test")
+			assertError(t, err, nil)
+			assertStrings(t, got, "new definition")
+
+			_, err = dictionary.Search("other")
+			assertError(t, err, ErrNotFound)
+
+			words, err := dictionary.Dictionary()
+			assertError(t, err, nil)
+			if want := (Dictionary{"test": "new definition"}); !reflect.DeepEqual(words, want) {
+				t.Errorf("got %v want %v", words, want)
+			}
+		})
+	}
+}
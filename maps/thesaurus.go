@@ -0,0 +1,267 @@
+package maps
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	ErrSelfRelation = DictionaryErr("cannot relate a word to itself")
+	ErrNoPath       = DictionaryErr("could not find a relation path between the words")
+)
+
+type RelationKind int
+
+const (
+	Synonym RelationKind = iota
+	Antonym
+	Hypernym
+	Hyponym
+	SeeAlso
+)
+
+func (k RelationKind) String() string {
+	switch k {
+	case Synonym:
+		return "synonym"
+	case Antonym:
+		return "antonym"
+	case Hypernym:
+		return "hypernym"
+	case Hyponym:
+		return "hyponym"
+	case SeeAlso:
+		return "see also"
+	default:
+		return fmt.Sprintf("RelationKind(%d)", int(k))
+	}
+}
+
+// Inverse is the kind of the relation pointing the other way: the inverse of
+// a hypernym is a hyponym, and synonyms, antonyms and see-also are symmetric.
+func (k RelationKind) Inverse() RelationKind {
+	switch k {
+	case Hypernym:
+		return Hyponym
+	case Hyponym:
+		return Hypernym
+	default:
+		return k
+	}
+}
+
+// Relation says that To is a Kind of From, e.g. {"fast", "quick", Synonym}.
+type Relation struct {
+	From string
+	To   string
+	Kind RelationKind
+}
+
+// Reachable is a word found by Closure and the number of relations followed to
+// reach it.
+type Reachable struct {
+	Word  string
+	Depth int
+}
+
+// Thesaurus wraps a Dictionary with typed relations between its words.
+type Thesaurus struct {
+	dictionary Dictionary
+	outgoing   map[string]map[Relation]bool
+	// incoming maps a word to the words with a relation to it.
+	incoming map[string]map[string]bool
+}
+
+func NewThesaurus(dictionary Dictionary) *Thesaurus {
+	if dictionary == nil {
+		dictionary = Dictionary{}
+	}
+	return &Thesaurus{dictionary: dictionary, outgoing: map[string]map[Relation]bool{}, incoming: map[string]map[string]bool{}}
+}
+
+func (t *Thesaurus) Search(word string) (string, error) {
+	return t.dictionary.Search(word)
+}
+
+func (t *Thesaurus) Add(word, definition string) error {
+	return t.dictionary.Add(word, definition)
+}
+
+func (t *Thesaurus) Update(word, definition string) error {
+	return t.dictionary.Update(word, definition)
+}
+
+// Delete removes word along with every relation from or to it.
+func (t *Thesaurus) Delete(word string) error {
+	if _, err := t.dictionary.Search(word); err != nil {
+		return err
+	}
+	t.dictionary.Delete(word)
+
+	for relation := range t.outgoing[word] {
+		delete(t.incoming[relation.To], word)
+	}
+	for from := range t.incoming[word] {
+		for relation := range t.outgoing[from] {
+			if relation.To == word {
+				delete(t.outgoing[from], relation)
+			}
+		}
+	}
+	delete(t.outgoing, word)
+	delete(t.incoming, word)
+	return nil
+}
+
+// Relate records that to is a kind of from. A bidirectional relation also
+// records the inverse relation from to back to from.
+func (t *Thesaurus) Relate(from, to string, kind RelationKind, bidirectional bool) error {
+	if from == to {
+		return ErrSelfRelation
+	}
+	for _, word := range []string{from, to} {
+		if _, err := t.dictionary.Search(word); err != nil {
+			return err
+		}
+	}
+
+	t.link(Relation{from, to, kind})
+	if bidirectional {
+		t.link(Relation{to, from, kind.Inverse()})
+	}
+	return nil
+}
+
+// Unrelate removes a relation, and its inverse if bidirectional is set.
+func (t *Thesaurus) Unrelate(from, to string, kind RelationKind, bidirectional bool) {
+	t.unlink(Relation{from, to, kind})
+	if bidirectional {
+		t.unlink(Relation{to, from, kind.Inverse()})
+	}
+}
+
+// Related returns the relations from word of the given kinds, or of every kind
+// if none are given.
+func (t *Thesaurus) Related(word string, kinds ...RelationKind) ([]Relation, error) {
+	if _, err := t.dictionary.Search(word); err != nil {
+		return nil, err
+	}
+
+	var relations []Relation
+	for relation := range t.outgoing[word] {
+		if hasKind(kinds, relation.Kind) {
+			relations = append(relations, relation)
+		}
+	}
+	sort.Slice(relations, func(i, j int) bool {
+		if relations[i].Kind != relations[j].Kind {
+			return relations[i].Kind < relations[j].Kind
+		}
+		return relations[i].To < relations[j].To
+	})
+	return relations, nil
+}
+
+// Closure returns the words reachable from word by following at most depth
+// relations of the given kinds, nearest first.
+func (t *Thesaurus) Closure(word string, depth int, kinds ...RelationKind) ([]Reachable, error) {
+	if _, err := t.dictionary.Search(word); err != nil {
+		return nil, err
+	}
+
+	var reached []Reachable
+	t.walk(word, kinds, func(to string, path []Relation) bool {
+		if len(path) > depth {
+			return false
+		}
+		reached = append(reached, Reachable{to, len(path)})
+		return true
+	})
+	return reached, nil
+}
+
+// Path returns the shortest chain of relations of the given kinds leading from
+// one word to another.
+func (t *Thesaurus) Path(from, to string, kinds ...RelationKind) ([]Relation, error) {
+	for _, word := range []string{from, to} {
+		if _, err := t.dictionary.Search(word); err != nil {
+			return nil, err
+		}
+	}
+	if from == to {
+		return []Relation{}, nil
+	}
+
+	var found []Relation
+	t.walk(from, kinds, func(word string, path []Relation) bool {
+		if word == to {
+			found = path
+			return false
+		}
+		return found == nil
+	})
+	if found == nil {
+		return nil, ErrNoPath
+	}
+	return found, nil
+}
+
+// walk visits the words reachable from start breadth first, calling visit with
+// the shortest path to each. Words are not expanded past when visit returns false.
+func (t *Thesaurus) walk(start string, kinds []RelationKind, visit func(word string, path []Relation) bool) {
+	seen := map[string]bool{start: true}
+	queue := [][]Relation{{}}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+
+		word := start
+		if len(path) > 0 {
+			word = path[len(path)-1].To
+		}
+		relations, _ := t.Related(word, kinds...)
+		for _, relation := range relations {
+			if seen[relation.To] {
+				continue
+			}
+			seen[relation.To] = true
+			next := append(append([]Relation(nil), path...), relation)
+			if visit(relation.To, next) {
+				queue = append(queue, next)
+			}
+		}
+	}
+}
+
+func (t *Thesaurus) link(relation Relation) {
+	if t.outgoing[relation.From] == nil {
+		t.outgoing[relation.From] = map[Relation]bool{}
+	}
+	t.outgoing[relation.From][relation] = true
+	if t.incoming[relation.To] == nil {
+		t.incoming[relation.To] = map[string]bool{}
+	}
+	t.incoming[relation.To][relation.From] = true
+}
+
+func (t *Thesaurus) unlink(relation Relation) {
+	delete(t.outgoing[relation.From], relation)
+	for other := range t.outgoing[relation.From] {
+		if other.To == relation.To {
+			return
+		}
+	}
+	delete(t.incoming[relation.To], relation.From)
+}
+
+func hasKind(kinds []RelationKind, kind RelationKind) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package maps
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestThesaurus(t *testing.T) {
+	newThesaurus := func() *Thesaurus {
+		thesaurus := NewThesaurus(Dictionary{
+			"fast":    "moving quickly",
+			"quick":   "fast",
+			"rapid":   "happening quickly",
+			"slow":    "not fast",
+			"animal":  "a living organism",
+			"dog":     "a domesticated animal",
+			"poodle":  "a breed of dog",
+			"unicorn": "a mythical animal",
+		})
+		thesaurus.Relate("fast", "quick", Synonym, true)
+		thesaurus.Relate("quick", "rapid", Synonym, true)
+		thesaurus.Relate("fast", "slow", Antonym, true)
+		thesaurus.Relate("poodle", "dog", Hypernym, true)
+		thesaurus.Relate("dog", "animal", Hypernym, true)
+		return thesaurus
+	}
+
+	t.Run("direct relations", func(t *testing.T) {
+		got, err := newThesaurus().Related("fast")
+
+		assertError(t, err, nil)
+		want := []Relation{{"fast", "quick", Synonym}, {"fast", "slow", Antonym}}
+		assertRelations(t, got, want)
+	})
+
+	t.Run("bidirectional relations use the inverse kind", func(t *testing.T) {
+		got, _ := newThesaurus().Related("animal")
+
+		assertRelations(t, got, []Relation{{"animal", "dog", Hyponym}})
+	})
+
+	t.Run("filter by kind", func(t *testing.T) {
+		got, _ := newThesaurus().Related("fast", Antonym)
+
+		assertRelations(t, got, []Relation{{"fast", "slow", Antonym}})
+	})
+
+	t.Run("closure up to a depth", func(t *testing.T) {
+		thesaurus := newThesaurus()
+
+		got, _ := thesaurus.Closure("poodle", 1, Hypernym)
+		if want := []Reachable{{"dog", 1}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+
+		got, _ = thesaurus.Closure("poodle", 5, Hypernym)
+		if want := []Reachable{{"dog", 1}, {"animal", 2}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("shortest path", func(t *testing.T) {
+		thesaurus := newThesaurus()
+		thesaurus.Relate("fast", "rapid", SeeAlso, false)
+
+		got, err := thesaurus.Path("fast", "rapid")
+		assertError(t, err, nil)
+		assertRelations(t, got, []Relation{{"fast", "rapid", SeeAlso}})
+
+		got, err = thesaurus.Path("fast", "rapid", Synonym)
+		assertError(t, err, nil)
+		assertRelations(t, got, []Relation{{"fast", "quick", Synonym}, {"quick", "rapid", Synonym}})
+
+		_, err = thesaurus.Path("fast", "unicorn")
+		assertError(t, err, ErrNoPath)
+	})
+
+	t.Run("invalid relations", func(t *testing.T) {
+		thesaurus := newThesaurus()
+
+		assertError(t, thesaurus.Relate("fast", "fast", Synonym, false), ErrSelfRelation)
+		assertError(t, thesaurus.Relate("fast", "missing", Synonym, false), ErrNotFound)
+	})
+
+	t.Run("deleting a word removes its relations", func(t *testing.T) {
+		thesaurus := newThesaurus()
+
+		assertError(t, thesaurus.Delete("quick"), nil)
+
+		got, _ := thesaurus.Related("fast")
+		assertRelations(t, got, []Relation{{"fast", "slow", Antonym}})
+		got, _ = thesaurus.Related("rapid")
+		assertRelations(t, got, nil)
+		if _, ok := thesaurus.incoming["quick"]; ok {
+			t.Error("expected incoming relations to be removed")
+		}
+
+		thesaurus.Add("quick", "re-added")
+		got, _ = thesaurus.Related("quick")
+		assertRelations(t, got, nil)
+	})
+
+	t.Run("unrelate", func(t *testing.T) {
+		thesaurus := newThesaurus()
+
+		thesaurus.Unrelate("fast", "slow", Antonym, true)
+
+		got, _ := thesaurus.Related("slow")
+		assertRelations(t, got, nil)
+	})
+}
+
+func assertRelations(t *testing.T, got, want []Relation) {
+	t.Helper()
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
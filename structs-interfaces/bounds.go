@@ -0,0 +1,89 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrUnknownShape = errors.New("shape has no known outline")
+
+// Box is an axis-aligned bounding box.
+type Box struct {
+	Min Point
+	Max Point
+}
+
+func (b Box) Width() float64 {
+	return b.Max.X - b.Min.X
+}
+
+func (b Box) Height() float64 {
+	return b.Max.Y - b.Min.Y
+}
+
+func (b Box) Centre() Point {
+	return Point{(b.Min.X + b.Max.X) / 2, (b.Min.Y + b.Max.Y) / 2}
+}
+
+func (b Box) Area() float64 {
+	return b.Width() * b.Height()
+}
+
+// Intersects reports whether the boxes overlap or touch.
+func (b Box) Intersects(o Box) bool {
+	return b.Min.X <= o.Max.X && o.Min.X <= b.Max.X && b.Min.Y <= o.Max.Y && o.Min.Y <= b.Max.Y
+}
+
+func (b Box) ContainsPoint(p Point) bool {
+	return b.Min.X <= p.X && p.X <= b.Max.X && b.Min.Y <= p.Y && p.Y <= b.Max.Y
+}
+
+func (b Box) ContainsBox(o Box) bool {
+	return b.Min.X <= o.Min.X && o.Max.X <= b.Max.X && b.Min.Y <= o.Min.Y && o.Max.Y <= b.Max.Y
+}
+
+// Union is the smallest box holding both boxes.
+func (b Box) Union(o Box) Box {
+	return Box{
+		Min: Point{math.Min(b.Min.X, o.Min.X), math.Min(b.Min.Y, o.Min.Y)},
+		Max: Point{math.Max(b.Max.X, o.Max.X), math.Max(b.Max.Y, o.Max.Y)},
+	}
+}
+
+// Rectangle returns the box as a Rectangle placed where the box is.
+func (b Box) Rectangle() Placed {
+	return Place(Rectangle{b.Width(), b.Height()}, Translate(b.Min.X, b.Min.Y))
+}
+
+// BoundsOf returns the bounding box of shape in the coordinates its outlines
+// use, see OutlinesOf. A shape that covers nothing has no bounds.
+func BoundsOf(shape Shape) (Box, error) {
+	r, err := regionOf(shape)
+	if err != nil {
+		return Box{}, err
+	}
+	if len(r.parts) == 0 {
+		return Box{}, fmt.Errorf("%w: %T covers nothing", ErrInvalidDimension, shape)
+	}
+	return r.box, nil
+}
+
+func (o Outline) Bounds() Box {
+	if o.Curved {
+		// The unit circle mapped by Curve reaches furthest along x where the
+		// direction (cos t, sin t) lines up with (A, C), and likewise for y.
+		m := o.Curve
+		dx, dy := math.Hypot(m.A, m.C), math.Hypot(m.B, m.D)
+		return Box{Point{m.E - dx, m.F - dy}, Point{m.E + dx, m.F + dy}}
+	}
+	if len(o.Points) == 0 {
+		return Box{}
+	}
+
+	box := Box{o.Points[0], o.Points[0]}
+	for _, p := range o.Points[1:] {
+		box = box.Union(Box{p, p})
+	}
+	return box
+}
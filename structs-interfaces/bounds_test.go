@@ -0,0 +1,64 @@
+package structs_interfaces
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBoundsOf(t *testing.T) {
+	boundsTests := []struct {
+		shape Shape
+		want  Box
+	}{
+		{Rectangle{4, 2}, Box{Point{0, 0}, Point{4, 2}}},
+		{Circle{2}, Box{Point{-2, -2}, Point{2, 2}}},
+		{Place(Ellipse{3, 1}, Translate(10, 10)), Box{Point{7, 9}, Point{13, 11}}},
+		{Place(Ellipse{3, 1}, Rotate(math.Pi/2)), Box{Point{-1, -3}, Point{1, 3}}},
+		{Place(Square{2}, Rotate(math.Pi/4)), Box{Point{-math.Sqrt2, 0}, Point{math.Sqrt2, 2 * math.Sqrt2}}},
+		{Polygon{lShape}, Box{Point{0, 0}, Point{4, 3}}},
+		{Triangle{4, 3}, Box{Point{0, 0}, Point{4, 3}}},
+	}
+
+	for _, tt := range boundsTests {
+		got, err := BoundsOf(tt.shape)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPoint(t, got.Min, tt.want.Min)
+		assertPoint(t, got.Max, tt.want.Max)
+	}
+
+	t.Run("rotated ellipse bounds are tight", func(t *testing.T) {
+		ellipse := Place(Ellipse{3, 1}, Rotate(0.6))
+		box, _ := BoundsOf(ellipse)
+		outline, _ := OutlineOf(ellipse)
+
+		maxX := math.Inf(-1)
+		for i := 0; i < 10000; i++ {
+			sin, cos := math.Sincos(2 * math.Pi * float64(i) / 10000)
+			maxX = math.Max(maxX, outline.Curve.Apply(Point{cos, sin}).X)
+		}
+		if math.Abs(box.Max.X-maxX) > 1e-6 {
+			t.Errorf("got max x %g want %g", box.Max.X, maxX)
+		}
+	})
+}
+
+func TestBox(t *testing.T) {
+	a := Box{Point{0, 0}, Point{2, 2}}
+	b := Box{Point{2, 1}, Point{3, 3}}
+	c := Box{Point{5, 5}, Point{6, 6}}
+
+	if !a.Intersects(b) || a.Intersects(c) {
+		t.Error("unexpected box intersection")
+	}
+	if got := a.Union(c); got != (Box{Point{0, 0}, Point{6, 6}}) {
+		t.Errorf("got union %v", got)
+	}
+	if !a.ContainsPoint(Point{1, 2}) || a.ContainsPoint(Point{3, 1}) {
+		t.Error("unexpected point containment")
+	}
+	if got := a.Rectangle().Area(); got != 4 {
+		t.Errorf("got area %g want 4", got)
+	}
+}
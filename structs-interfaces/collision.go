@@ -0,0 +1,338 @@
+package structs_interfaces
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Pair holds the indexes of two colliding shapes, I < J.
+type Pair struct {
+	I int
+	J int
+}
+
+// Intersects reports whether two shapes overlap or touch. Circles,
+// rectangles and polygons are tested exactly, using the separating axis
+// theorem for convex polygons. Ellipses that are not circles are tested
+// against each other numerically. Shapes with several parts, see OutlinesOf,
+// are tested against the area their parts wind around, so nothing inside a
+// hole touches them.
+func Intersects(a, b Shape) (bool, error) {
+	ra, err := collisionRegion(a)
+	if err != nil {
+		return false, err
+	}
+	rb, err := collisionRegion(b)
+	if err != nil {
+		return false, err
+	}
+	return regionsIntersect(ra, rb), nil
+}
+
+// collisionRegion is the region inside shape, whose straight-sided parts must
+// have at least three corners to be tested for collisions.
+func collisionRegion(shape Shape) (region, error) {
+	outlines, err := OutlinesOf(shape)
+	if err != nil {
+		return region{}, err
+	}
+	for _, outline := range outlines {
+		if !outline.Curved && len(outline.Points) < 3 {
+			return region{}, fmt.Errorf("%w: %T has %d corners", ErrInvalidDimension, shape, len(outline.Points))
+		}
+	}
+	return newRegion(outlines), nil
+}
+
+// Collisions returns every pair of shapes that intersect, sorted. Bounding
+// boxes are swept along the x axis so only shapes whose boxes overlap are
+// tested exactly. Shapes that cover nothing collide with nothing.
+func Collisions(shapes []Shape) ([]Pair, error) {
+	regions := make([]region, len(shapes))
+	var order []int
+	for i, shape := range shapes {
+		r, err := collisionRegion(shape)
+		if err != nil {
+			return nil, fmt.Errorf("shape %d: %w", i, err)
+		}
+		regions[i] = r
+		if len(r.parts) > 0 {
+			order = append(order, i)
+		}
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return regions[order[a]].box.Min.X < regions[order[b]].box.Min.X
+	})
+
+	var pairs []Pair
+	var active []int
+	for _, i := range order {
+		kept := active[:0]
+		for _, j := range active {
+			if regions[j].box.Max.X >= regions[i].box.Min.X {
+				kept = append(kept, j)
+			}
+		}
+		active = kept
+
+		for _, j := range active {
+			if regions[i].box.Intersects(regions[j].box) && regionsIntersect(regions[i], regions[j]) {
+				if i < j {
+					pairs = append(pairs, Pair{i, j})
+				} else {
+					pairs = append(pairs, Pair{j, i})
+				}
+			}
+		}
+		active = append(active, i)
+	}
+
+	sort.Slice(pairs, func(a, b int) bool {
+		if pairs[a].I != pairs[b].I {
+			return pairs[a].I < pairs[b].I
+		}
+		return pairs[a].J < pairs[b].J
+	})
+	return pairs, nil
+}
+
+// regionsIntersect tests single outlines exactly with outlinesIntersect. With
+// several parts, the regions meet if their boundaries do, and otherwise only
+// if one holds a whole loop of the other's boundary.
+func regionsIntersect(a, b region) bool {
+	if len(a.parts) == 0 || len(b.parts) == 0 || !a.box.Intersects(b.box) {
+		return false
+	}
+	if len(a.parts) == 1 && len(b.parts) == 1 {
+		return outlinesIntersect(a.parts[0], b.parts[0])
+	}
+	for _, pa := range a.parts {
+		for _, pb := range b.parts {
+			if boundariesMeet(pa, pb) {
+				return true
+			}
+		}
+	}
+	for _, pa := range a.parts {
+		if b.contains(pointOn(pa), epsilon) {
+			return true
+		}
+	}
+	for _, pb := range b.parts {
+		if a.contains(pointOn(pb), epsilon) {
+			return true
+		}
+	}
+	return false
+}
+
+// boundariesMeet reports whether the boundaries of two outlines cross or
+// touch. A region with several parts has no curves, so at most one of them
+// is curved.
+func boundariesMeet(a, b Outline) bool {
+	if !a.Bounds().Intersects(b.Bounds()) {
+		return false
+	}
+	if a.Curved {
+		a, b = b, a
+	}
+	if b.Curved {
+		// Mapped back to the unit circle, an edge meets it if it comes
+		// within 1 of the origin and does not lie wholly inside.
+		inverse, err := b.Curve.Invert()
+		if err != nil {
+			return false
+		}
+		for i, p := range a.Points {
+			p, q := inverse.Apply(p), inverse.Apply(a.Points[(i+1)%len(a.Points)])
+			far := math.Max(math.Hypot(p.X, p.Y), math.Hypot(q.X, q.Y))
+			if distanceToSegment(Point{}, p, q) <= 1+epsilon && far >= 1-epsilon {
+				return true
+			}
+		}
+		return false
+	}
+	for i, a1 := range a.Points {
+		a2 := a.Points[(i+1)%len(a.Points)]
+		for j, b1 := range b.Points {
+			if segmentsIntersect(a1, a2, b1, b.Points[(j+1)%len(b.Points)]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pointOn returns a point on the outline's boundary.
+func pointOn(o Outline) Point {
+	if o.Curved {
+		return o.Curve.Apply(Point{1, 0})
+	}
+	return o.Points[0]
+}
+
+func outlinesIntersect(a, b Outline) bool {
+	if !a.Bounds().Intersects(b.Bounds()) {
+		return false
+	}
+	switch {
+	case a.Curved && b.Curved:
+		return curvesIntersect(a.Curve, b.Curve)
+	case a.Curved:
+		return curveIntersectsPolygon(a.Curve, b.Points)
+	case b.Curved:
+		return curveIntersectsPolygon(b.Curve, a.Points)
+	default:
+		return polygonsIntersect(a.Points, b.Points)
+	}
+}
+
+// curveIntersectsPolygon maps the curve back to the unit circle, which keeps
+// whether the shapes meet, and then checks whether the circle's centre is in
+// the polygon or an edge comes within its radius.
+func curveIntersectsPolygon(curve Matrix, points []Point) bool {
+	inverse, err := curve.Invert()
+	if err != nil {
+		return pointInPolygon(Point{curve.E, curve.F}, points)
+	}
+
+	mapped := make([]Point, len(points))
+	for i, p := range points {
+		mapped[i] = inverse.Apply(p)
+	}
+	origin := Point{}
+	if pointInPolygon(origin, mapped) {
+		return true
+	}
+	for i, a := range mapped {
+		if distanceToSegment(origin, a, mapped[(i+1)%len(mapped)]) <= 1+epsilon {
+			return true
+		}
+	}
+	return false
+}
+
+func curvesIntersect(a, b Matrix) bool {
+	if a.scalesEvenly() && b.scalesEvenly() {
+		radiusA, _ := a.Stretch()
+		radiusB, _ := b.Stretch()
+		return distance(Point{a.E, a.F}, Point{b.E, b.F}) <= radiusA+radiusB+epsilon
+	}
+
+	inverse, err := a.Invert()
+	if err != nil {
+		return pointInCurve(Point{a.E, a.F}, b)
+	}
+	// With a mapped to the unit circle, the shapes meet if the mapped b
+	// holds the origin or its boundary comes within 1 of it.
+	mapped := b.Then(inverse)
+	return pointInCurve(Point{}, mapped) || closestOnCurve(Point{}, mapped) <= 1+epsilon
+}
+
+func pointInCurve(p Point, curve Matrix) bool {
+	inverse, err := curve.Invert()
+	if err != nil {
+		return false
+	}
+	q := inverse.Apply(p)
+	return q.X*q.X+q.Y*q.Y <= 1+epsilon
+}
+
+// closestOnCurve finds the distance from p to the boundary of the unit circle
+// mapped by curve, by sampling it and then narrowing in on the nearest sample
+// with a golden section search.
+func closestOnCurve(p Point, curve Matrix) float64 {
+	at := func(angle float64) float64 {
+		sin, cos := math.Sincos(angle)
+		return distance(p, curve.Apply(Point{cos, sin}))
+	}
+
+	const samples = 256
+	step := 2 * math.Pi / samples
+	best := 0.0
+	for i := 1; i < samples; i++ {
+		if at(float64(i)*step) < at(best) {
+			best = float64(i) * step
+		}
+	}
+
+	ratio := (math.Sqrt(5) - 1) / 2
+	lo, hi := best-step, best+step
+	for i := 0; i < 100 && hi-lo > 1e-12; i++ {
+		m1, m2 := hi-ratio*(hi-lo), lo+ratio*(hi-lo)
+		if at(m1) < at(m2) {
+			hi = m2
+		} else {
+			lo = m1
+		}
+	}
+	return at((lo + hi) / 2)
+}
+
+func polygonsIntersect(a, b []Point) bool {
+	if (Polygon{a}).IsConvex() && (Polygon{b}).IsConvex() {
+		return !separated(a, b) && !separated(b, a)
+	}
+
+	for i, a1 := range a {
+		a2 := a[(i+1)%len(a)]
+		for j, b1 := range b {
+			if segmentsIntersect(a1, a2, b1, b[(j+1)%len(b)]) {
+				return true
+			}
+		}
+	}
+	// With no crossing edges the polygons either nest or are apart.
+	return pointInPolygon(a[0], b) || pointInPolygon(b[0], a)
+}
+
+// separated reports whether one of a's edge normals is a separating axis
+// between the convex polygons a and b.
+func separated(a, b []Point) bool {
+	for i, p := range a {
+		edge := sub(a[(i+1)%len(a)], p)
+		axis := Point{-edge.Y, edge.X}
+		minA, maxA := project(a, axis)
+		minB, maxB := project(b, axis)
+		if maxA < minB-epsilon || maxB < minA-epsilon {
+			return true
+		}
+	}
+	return false
+}
+
+func project(points []Point, axis Point) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		d := dot(p, axis)
+		lo, hi = math.Min(lo, d), math.Max(hi, d)
+	}
+	return lo, hi
+}
+
+// pointInPolygon counts the polygon's edges crossing a ray to the right of p.
+// Points on an edge are inside.
+func pointInPolygon(p Point, points []Point) bool {
+	inside := false
+	for i, a := range points {
+		b := points[(i+1)%len(points)]
+		if onSegment(a, b, p) {
+			return true
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func distanceToSegment(p, a, b Point) float64 {
+	ab := sub(b, a)
+	length := dot(ab, ab)
+	if length == 0 {
+		return distance(p, a)
+	}
+	t := math.Max(0, math.Min(1, dot(sub(p, a), ab)/length))
+	return distance(p, Point{a.X + t*ab.X, a.Y + t*ab.Y})
+}
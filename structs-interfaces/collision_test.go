@@ -0,0 +1,107 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestIntersects(t *testing.T) {
+	intersectionTests := []struct {
+		name string
+		a, b Shape
+		want bool
+	}{
+		{"overlapping circles", Circle{2}, Place(Circle{2}, Translate(3, 0)), true},
+		{"touching circles", Circle{2}, Place(Circle{1}, Translate(3, 0)), true},
+		{"separate circles", Circle{2}, Place(Circle{1}, Translate(3.1, 0)), false},
+		{"overlapping rectangles", Rectangle{2, 2}, Place(Rectangle{2, 2}, Translate(1, 1)), true},
+		{"separate rectangles", Rectangle{2, 2}, Place(Rectangle{2, 2}, Translate(3, 0)), false},
+		{"rotated rectangles", Rectangle{2, 2}, Place(Square{2}, Rotate(math.Pi/4).Then(Translate(4.5, 0))), false},
+		{"rotated rectangles overlapping", Rectangle{2, 2}, Place(Square{2}, Rotate(math.Pi/4).Then(Translate(3.4, 0))), true},
+		{"circle near rectangle corner", Place(Circle{1}, Translate(2.8, 2.8)), Rectangle{2, 2}, false},
+		{"circle over rectangle edge", Place(Circle{1}, Translate(2.5, 1)), Rectangle{2, 2}, true},
+		{"circle inside rectangle", Place(Circle{0.5}, Translate(1, 1)), Rectangle{2, 2}, true},
+		{"rectangle inside circle", Circle{10}, Rectangle{1, 1}, true},
+		{"ellipse and rectangle", Ellipse{3, 1}, Place(Rectangle{1, 1}, Translate(2.5, 0.5)), true},
+		{"ellipse misses rectangle", Ellipse{3, 1}, Place(Rectangle{1, 1}, Translate(2.5, 0.9)), false},
+		{"ellipses", Ellipse{3, 1}, Place(Ellipse{1, 3}, Translate(3.5, 0)), true},
+		{"ellipses apart", Ellipse{3, 1}, Place(Ellipse{1, 3}, Translate(4.1, 0)), false},
+		{"crossed ellipses", Ellipse{3, 0.5}, Ellipse{0.5, 3}, true},
+		{"concave polygons apart", Polygon{lShape}, Place(Square{1}, Translate(2, 2)), false},
+		{"concave polygons overlapping", Polygon{lShape}, Place(Square{1}, Translate(0.5, 2)), true},
+		{"nested polygons", Place(Polygon{lShape}, Scale(10, 10)), Place(Square{1}, Translate(2, 2)), true},
+	}
+
+	for _, tt := range intersectionTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Intersects(tt.a, tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+			if reversed, _ := Intersects(tt.b, tt.a); reversed != got {
+				t.Error("intersection is not symmetric")
+			}
+		})
+	}
+}
+
+type blob struct{}
+
+func (blob) Area() float64      { return 1 }
+func (blob) Perimeter() float64 { return 1 }
+
+func TestCollisions(t *testing.T) {
+	shapes := []Shape{
+		Rectangle{2, 2},
+		Place(Circle{1}, Translate(2.5, 1)),
+		Place(Rectangle{1, 1}, Translate(10, 10)),
+		Place(Circle{1}, Translate(10, 11)),
+		Place(Square{1}, Translate(-5, 0)),
+		Place(Circle{1}, Translate(3, 1)),
+	}
+
+	got, err := Collisions(shapes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Pair{{0, 1}, {0, 5}, {1, 5}, {2, 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	t.Run("matches testing every pair", func(t *testing.T) {
+		var brute []Pair
+		for i := range shapes {
+			for j := i + 1; j < len(shapes); j++ {
+				if hit, _ := Intersects(shapes[i], shapes[j]); hit {
+					brute = append(brute, Pair{i, j})
+				}
+			}
+		}
+		if !reflect.DeepEqual(got, brute) {
+			t.Errorf("got %v want %v", got, brute)
+		}
+	})
+
+	t.Run("unknown shapes", func(t *testing.T) {
+		_, err := Collisions([]Shape{Rectangle{1, 1}, blob{}})
+		if !errors.Is(err, ErrUnknownShape) {
+			t.Errorf("got %v want %v", err, ErrUnknownShape)
+		}
+	})
+
+	t.Run("shapes without corners", func(t *testing.T) {
+		if _, err := Intersects(Polygon{}, Square{1}); !errors.Is(err, ErrInvalidDimension) {
+			t.Errorf("got %v want %v", err, ErrInvalidDimension)
+		}
+		if _, err := Collisions([]Shape{RegularPolygon{2, 1}, Square{1}}); !errors.Is(err, ErrInvalidDimension) {
+			t.Errorf("got %v want %v", err, ErrInvalidDimension)
+		}
+	})
+}
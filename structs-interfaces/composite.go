@@ -0,0 +1,513 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var ErrNotTriangulable = errors.New("polygon cannot be split into triangles")
+
+// DefaultTolerance is how far, at most, the polygons standing in for curves
+// stray from them when a Composite has no Tolerance of its own.
+const DefaultTolerance = 1e-3
+
+type BooleanOp int
+
+const (
+	UnionOp BooleanOp = iota
+	IntersectionOp
+	DifferenceOp
+)
+
+var booleanOpNames = map[BooleanOp]string{
+	UnionOp:        "union",
+	IntersectionOp: "intersection",
+	DifferenceOp:   "difference",
+}
+
+func (op BooleanOp) String() string {
+	if name, ok := booleanOpNames[op]; ok {
+		return name
+	}
+	return fmt.Sprintf("BooleanOp(%d)", int(op))
+}
+
+func (op BooleanOp) MarshalText() ([]byte, error) {
+	if _, ok := booleanOpNames[op]; !ok {
+		return nil, fmt.Errorf("unknown boolean operation %d", int(op))
+	}
+	return []byte(op.String()), nil
+}
+
+func (op *BooleanOp) UnmarshalText(text []byte) error {
+	for o, name := range booleanOpNames {
+		if name == string(text) {
+			*op = o
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown boolean operation %q", text)
+}
+
+// Composite is the union or intersection of Shapes, or the first of them
+// minus the rest. Polygonal shapes are clipped exactly. Curves are replaced by
+// polygons with the same area that stay within Tolerance of them, so where
+// curves cross other edges the area can be off by up to about Tolerance times
+// the length of the curve involved.
+type Composite struct {
+	Op        BooleanOp `json:"op"`
+	Shapes    Shapes    `json:"shapes"`
+	Tolerance float64   `json:"tolerance,omitempty"`
+}
+
+func Union(shapes ...Shape) Composite {
+	return Composite{Op: UnionOp, Shapes: shapes}
+}
+
+func Intersection(shapes ...Shape) Composite {
+	return Composite{Op: IntersectionOp, Shapes: shapes}
+}
+
+// Difference is from with every one of minus cut out of it, like a room
+// minus its pillars.
+func Difference(from Shape, minus ...Shape) Composite {
+	return Composite{Op: DifferenceOp, Shapes: append(Shapes{from}, minus...)}
+}
+
+// Area is NaN if the composite is invalid.
+func (c Composite) Area() float64 {
+	pieces, err := c.pieces()
+	if err != nil {
+		return math.NaN()
+	}
+	var sum float64
+	for _, piece := range pieces {
+		sum += Polygon{piece}.Area()
+	}
+	return sum
+}
+
+// Perimeter is the length of the boundary of the result, so edges where
+// shapes meet inside it are not counted. It is NaN if the composite is
+// invalid or one of its shapes has no known perimeter.
+func (c Composite) Perimeter() float64 {
+	for _, shape := range c.Shapes {
+		if math.IsNaN(shape.Perimeter()) {
+			return math.NaN()
+		}
+	}
+	pieces, err := c.pieces()
+	if err != nil {
+		return math.NaN()
+	}
+	return boundaryLength(pieces)
+}
+
+func (c Composite) Validate() error {
+	if _, ok := booleanOpNames[c.Op]; !ok {
+		return fmt.Errorf("%w: unknown boolean operation %d", ErrInvalidDimension, int(c.Op))
+	}
+	if len(c.Shapes) == 0 {
+		return fmt.Errorf("%w: composite has no shapes", ErrInvalidDimension)
+	}
+	if c.Tolerance < 0 || math.IsNaN(c.Tolerance) {
+		return fmt.Errorf("%w: tolerance must not be negative, got %g", ErrInvalidDimension, c.Tolerance)
+	}
+	for i, shape := range c.Shapes {
+		if shape == nil {
+			return fmt.Errorf("%w: shape %d is missing", ErrInvalidDimension, i)
+		}
+		if err := Validate(shape); err != nil {
+			return fmt.Errorf("shape %d: %w", i, err)
+		}
+	}
+	_, err := c.pieces()
+	return err
+}
+
+// Polygons returns the result as convex polygons that do not overlap,
+// counter-clockwise.
+func (c Composite) Polygons() ([]Polygon, error) {
+	pieces, err := c.pieces()
+	if err != nil {
+		return nil, err
+	}
+	polygons := make([]Polygon, len(pieces))
+	for i, piece := range pieces {
+		polygons[i] = Polygon{piece}
+	}
+	return polygons, nil
+}
+
+func (c Composite) pieces() ([][]Point, error) {
+	tolerance := c.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	var result [][]Point
+	for i, shape := range c.Shapes {
+		pieces, err := piecesOf(shape, tolerance)
+		if err != nil {
+			return nil, fmt.Errorf("shape %d: %w", i, err)
+		}
+		switch {
+		case i == 0:
+			result = pieces
+		case c.Op == UnionOp:
+			result = append(result, subtractPieces(pieces, result)...)
+		case c.Op == IntersectionOp:
+			result = intersectPieces(result, pieces)
+		case c.Op == DifferenceOp:
+			result = subtractPieces(result, pieces)
+		default:
+			return nil, fmt.Errorf("%w: unknown boolean operation %d", ErrInvalidDimension, int(c.Op))
+		}
+	}
+	return result, nil
+}
+
+// piecesOf splits a shape into convex polygons that do not overlap.
+func piecesOf(shape Shape, tolerance float64) ([][]Point, error) {
+	switch s := shape.(type) {
+	case Composite:
+		if s.Tolerance == 0 {
+			s.Tolerance = tolerance
+		}
+		return s.pieces()
+	case MultiPolygon:
+		shapes := make(Shapes, len(s.Polygons))
+		for i, polygon := range s.Polygons {
+			shapes[i] = polygon
+		}
+		return Composite{UnionOp, shapes, tolerance}.pieces()
+	case Placed:
+		if _, ok := OutlineOf(s); !ok {
+			return placedPieces(s, tolerance)
+		}
+	}
+
+	outline, ok := OutlineOf(shape)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnknownShape, shape)
+	}
+	if outline.Curved {
+		return [][]Point{curvePolygon(outline.Curve, tolerance)}, nil
+	}
+	if !(Polygon{outline.Points}).IsSimple() {
+		return nil, ErrSelfIntersecting
+	}
+	return triangulate(outline.Points)
+}
+
+// placedPieces moves the pieces of a shape, such as a Composite, that has no
+// outline of its own.
+func placedPieces(p Placed, tolerance float64) ([][]Point, error) {
+	pieces, err := piecesOf(p.Shape, tolerance)
+	if err != nil {
+		return nil, err
+	}
+	moved := make([][]Point, len(pieces))
+	for i, piece := range pieces {
+		moved[i] = Outline{Points: piece}.Transform(p.Transform).Points
+	}
+	return moved, nil
+}
+
+// curvePolygon stands in for the unit circle mapped by curve. Its corners are
+// pushed out just enough to give it the same area as the curve, and there are
+// enough of them that it is never more than tolerance from it.
+func curvePolygon(curve Matrix, tolerance float64) []Point {
+	major, _ := curve.Stretch()
+	n := 8
+	if major > tolerance {
+		n = int(math.Max(float64(n), math.Ceil(math.Pi/math.Acos(1-tolerance/major))))
+	}
+	if n > 1<<16 {
+		n = 1 << 16
+	}
+
+	step := 2 * math.Pi / float64(n)
+	radius := math.Sqrt(step / math.Sin(step))
+	points := make([]Point, n)
+	for i := range points {
+		sin, cos := math.Sincos(float64(i) * step)
+		points[i] = curve.Apply(Point{radius * cos, radius * sin})
+	}
+	if curve.Determinant() < 0 {
+		reverse(points)
+	}
+	return points
+}
+
+// triangulate splits a simple counter-clockwise polygon into triangles by
+// clipping ears: corners whose triangle holds no other vertex.
+func triangulate(points []Point) ([][]Point, error) {
+	remaining := simplify(points)
+	if len(remaining) < 3 {
+		return nil, nil
+	}
+	var triangles [][]Point
+	for len(remaining) > 3 {
+		n := len(remaining)
+		clipped := false
+		for i := range remaining {
+			a, b, c := remaining[(i+n-1)%n], remaining[i], remaining[(i+1)%n]
+			if cross(a, b, c) <= epsilon || !isEar(remaining, a, b, c) {
+				continue
+			}
+			triangles = append(triangles, []Point{a, b, c})
+			remaining = simplify(append(remaining[:i:i], remaining[i+1:]...))
+			clipped = true
+			break
+		}
+		if !clipped {
+			return nil, ErrNotTriangulable
+		}
+		if len(remaining) < 3 {
+			return triangles, nil
+		}
+	}
+	return append(triangles, remaining), nil
+}
+
+func isEar(points []Point, a, b, c Point) bool {
+	for _, p := range points {
+		if p == a || p == b || p == c {
+			continue
+		}
+		if cross(a, b, p) >= -epsilon && cross(b, c, p) >= -epsilon && cross(c, a, p) >= -epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// simplify drops repeated points and points in a straight line with their
+// neighbours.
+func simplify(points []Point) []Point {
+	out := append([]Point(nil), points...)
+	for changed := true; changed && len(out) >= 3; {
+		changed = false
+		for i := 0; i < len(out) && len(out) >= 3; i++ {
+			n := len(out)
+			a, b, c := out[(i+n-1)%n], out[i], out[(i+1)%n]
+			if distance(a, b) < epsilon || math.Abs(cross(a, b, c)) < epsilon*math.Max(1, distance(a, c)) {
+				out = append(out[:i], out[i+1:]...)
+				changed = true
+				i--
+			}
+		}
+	}
+	return out
+}
+
+func intersectPieces(a, b [][]Point) [][]Point {
+	var result [][]Point
+	for _, p := range a {
+		boxP := Outline{Points: p}.Bounds()
+		for _, q := range b {
+			if !boxP.Intersects(Outline{Points: q}.Bounds()) {
+				continue
+			}
+			if piece := intersectConvex(p, q); piece != nil {
+				result = append(result, piece)
+			}
+		}
+	}
+	return result
+}
+
+// subtractPieces cuts every piece of b out of every piece of a.
+func subtractPieces(a, b [][]Point) [][]Point {
+	result := a
+	for _, q := range b {
+		boxQ := Outline{Points: q}.Bounds()
+		var next [][]Point
+		for _, p := range result {
+			if !boxQ.Intersects(Outline{Points: p}.Bounds()) {
+				next = append(next, p)
+				continue
+			}
+			next = append(next, subtractConvex(p, q)...)
+		}
+		result = next
+	}
+	return result
+}
+
+func intersectConvex(p, clip []Point) []Point {
+	for i, a := range clip {
+		p, _ = splitConvex(p, a, clip[(i+1)%len(clip)])
+		if p == nil {
+			return nil
+		}
+	}
+	return p
+}
+
+// subtractConvex returns p minus clip as convex pieces, by cutting off the
+// part of p outside each of clip's edges in turn.
+func subtractConvex(p, clip []Point) [][]Point {
+	var pieces [][]Point
+	for i, a := range clip {
+		inside, outside := splitConvex(p, a, clip[(i+1)%len(clip)])
+		if outside != nil {
+			pieces = append(pieces, outside)
+		}
+		if inside == nil {
+			break
+		}
+		p = inside
+	}
+	return pieces
+}
+
+// splitConvex cuts a convex polygon along the line through a and b into the
+// parts to its left and right. A part with no area is nil.
+func splitConvex(points []Point, a, b Point) ([]Point, []Point) {
+	length := distance(a, b)
+	side := func(p Point) float64 {
+		s := cross(a, b, p) / length
+		if math.Abs(s) < epsilon {
+			return 0
+		}
+		return s
+	}
+
+	var left, right []Point
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		sp, sq := side(p), side(q)
+		if sp >= 0 {
+			left = append(left, p)
+		}
+		if sp <= 0 {
+			right = append(right, p)
+		}
+		if sp*sq < 0 {
+			t := sp / (sp - sq)
+			x := Point{p.X + t*(q.X-p.X), p.Y + t*(q.Y-p.Y)}
+			left, right = append(left, x), append(right, x)
+		}
+	}
+	return keepPiece(left), keepPiece(right)
+}
+
+func keepPiece(points []Point) []Point {
+	points = simplify(points)
+	if len(points) < 3 || (Polygon{points}).Area() < epsilon {
+		return nil
+	}
+	return points
+}
+
+// boundaryLength adds up the edges of the pieces, leaving out the parts of
+// edges that run back along an edge of another piece, which are inside.
+func boundaryLength(pieces [][]Point) float64 {
+	var total float64
+	for _, edge := range boundaryEdges(pieces) {
+		total += distance(edge[0], edge[1])
+	}
+	return total
+}
+
+// boundaryEdges returns the parts of the pieces' edges that do not run back
+// along an edge of another piece, in the direction the piece goes round.
+func boundaryEdges(pieces [][]Point) [][2]Point {
+	boxes := make([]Box, len(pieces))
+	for i, piece := range pieces {
+		boxes[i] = Outline{Points: piece}.Bounds()
+	}
+
+	var edges [][2]Point
+	for i, piece := range pieces {
+		for k, a := range piece {
+			b := piece[(k+1)%len(piece)]
+			length := distance(a, b)
+			dir := Point{(b.X - a.X) / length, (b.Y - a.Y) / length}
+			edgeBox := Box{a, a}.Union(Box{b, b})
+
+			type span struct{ from, to float64 }
+			var covered []span
+			for j, other := range pieces {
+				if j == i || !boxes[j].Intersects(edgeBox) {
+					continue
+				}
+				for m, c := range other {
+					d := other[(m+1)%len(other)]
+					if dot(sub(d, c), dir) >= 0 ||
+						math.Abs(cross(a, b, c))/length > 1e-7 || math.Abs(cross(a, b, d))/length > 1e-7 {
+						continue
+					}
+					from := math.Max(0, dot(sub(d, a), dir))
+					to := math.Min(length, dot(sub(c, a), dir))
+					if to > from {
+						covered = append(covered, span{from, to})
+					}
+				}
+			}
+
+			sort.Slice(covered, func(x, y int) bool { return covered[x].from < covered[y].from })
+			at := func(t float64) Point {
+				if t >= length {
+					return b
+				}
+				return Point{a.X + dir.X*t, a.Y + dir.Y*t}
+			}
+			reach := 0.0
+			for _, s := range covered {
+				if s.to <= reach {
+					continue
+				}
+				if s.from > reach {
+					edges = append(edges, [2]Point{at(reach), at(s.from)})
+				}
+				reach = s.to
+			}
+			if reach < length {
+				edges = append(edges, [2]Point{at(reach), b})
+			}
+		}
+	}
+	return edges
+}
+
+// boundaryRings joins the boundary edges of the pieces into closed rings,
+// counter-clockwise around the area they cover and clockwise around holes.
+func boundaryRings(pieces [][]Point) [][]Point {
+	edges := boundaryEdges(pieces)
+	used := make([]bool, len(edges))
+	meets := func(p, q Point) bool { return distance(p, q) <= 1e-7*math.Max(1, math.Hypot(p.X, p.Y)) }
+
+	var rings [][]Point
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		used[start] = true
+		ring := []Point{edges[start][0]}
+		end := edges[start][1]
+		for !meets(end, ring[0]) {
+			next := -1
+			for i, e := range edges {
+				if !used[i] && meets(e[0], end) {
+					next = i
+					break
+				}
+			}
+			if next < 0 {
+				break
+			}
+			used[next] = true
+			ring = append(ring, end)
+			end = edges[next][1]
+		}
+		if ring = simplify(ring); len(ring) >= 3 {
+			rings = append(rings, ring)
+		}
+	}
+	return rings
+}
@@ -0,0 +1,299 @@
+package structs_interfaces
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestComposite(t *testing.T) {
+	compositeTests := []struct {
+		name      string
+		shape     Composite
+		area      float64
+		perimeter float64
+	}{
+		{
+			"room minus pillars",
+			Difference(Rectangle{10, 8}, Place(Square{1}, Translate(2, 2)), Place(Square{1}, Translate(6, 5))),
+			78, 44,
+		},
+		{"overlapping squares", Union(Square{2}, Place(Square{2}, Translate(1, 1))), 7, 12},
+		{"overlap of squares", Intersection(Square{2}, Place(Square{2}, Translate(1, 1))), 1, 4},
+		{"corner cut from an L", Difference(Polygon{lShape}, Square{1}), 5, 14},
+		{"touching squares", Union(Square{1}, Place(Square{1}, Translate(1, 0))), 2, 6},
+		{"apart", Intersection(Square{1}, Place(Square{1}, Translate(5, 5))), 0, 0},
+		{"nothing cut", Difference(Square{1}, Place(Square{1}, Translate(5, 5))), 1, 4},
+		{"ring", Difference(Circle{1}, Circle{0.5}), 0.75 * math.Pi, 3 * math.Pi},
+		{
+			"nested and placed",
+			Union(Place(Difference(Square{4}, Place(Square{2}, Translate(1, 1))), Translate(10, 0)), Square{1}),
+			13, 28,
+		},
+	}
+
+	for _, tt := range compositeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.shape.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if !closeTo(tt.shape.Area(), tt.area, 1e-6) {
+				t.Errorf("got area %g want %g", tt.shape.Area(), tt.area)
+			}
+			if !closeTo(tt.shape.Perimeter(), tt.perimeter, 1e-2) {
+				t.Errorf("got perimeter %g want %g", tt.shape.Perimeter(), tt.perimeter)
+			}
+		})
+	}
+}
+
+func TestCompositeCurveTolerance(t *testing.T) {
+	// Two unit circles a unit apart overlap in a lens of area 2π/3 - √3/2.
+	want := 2*math.Pi/3 - math.Sqrt(3)/2
+	lens := Intersection(Circle{1}, Place(Circle{1}, Translate(1, 0)))
+
+	for _, tolerance := range []float64{1e-2, 1e-3, 1e-5} {
+		lens.Tolerance = tolerance
+		// The lens is bounded by two arcs a third of a circle long.
+		if got := lens.Area(); !closeTo(got, want, tolerance*4*math.Pi/3) {
+			t.Errorf("tolerance %g: got area %g want %g", tolerance, got, want)
+		}
+	}
+}
+
+func TestCompositeIdentities(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	for i := 0; i < 50; i++ {
+		a := Place(RegularPolygon{3 + random.Intn(6), 1 + random.Float64()}, Rotate(random.Float64()*math.Pi))
+		b := Place(Polygon{lShape}, Rotate(random.Float64()*math.Pi).Then(Translate(random.Float64()*2-1, random.Float64()*2-1)))
+
+		both := Intersection(a, b).Area()
+		if union := Union(a, b).Area(); !closeTo(union, a.Area()+b.Area()-both, 1e-6) {
+			t.Errorf("%v and %v: union %g, intersection %g", a, b, union, both)
+		}
+		if difference := Difference(a, b).Area(); !closeTo(difference, a.Area()-both, 1e-6) {
+			t.Errorf("%v and %v: difference %g, intersection %g", a, b, difference, both)
+		}
+
+		polygons, err := Union(a, b).Polygons()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range polygons {
+			if !p.IsConvex() || p.Orientation() != CounterClockwise {
+				t.Fatalf("%v is not a convex counter-clockwise piece", p)
+			}
+		}
+	}
+}
+
+func TestInvalidComposite(t *testing.T) {
+	cases := []struct {
+		name  string
+		shape Composite
+		want  error
+	}{
+		{"unknown shape", Union(Square{1}, blob{}), ErrUnknownShape},
+		{"invalid shape", Union(Square{-1}), ErrInvalidDimension},
+		{"no shapes", Union(), ErrInvalidDimension},
+		{"self-intersecting", Difference(Square{5}, Polygon{bowtie}), ErrSelfIntersecting},
+		{"unknown operation", Composite{Op: BooleanOp(9), Shapes: Shapes{Square{1}}}, ErrInvalidDimension},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.shape.Validate(); !errors.Is(err, c.want) {
+				t.Errorf("got %v want %v", err, c.want)
+			}
+		})
+	}
+
+	if area := Union(blob{}).Area(); !math.IsNaN(area) {
+		t.Errorf("got area %g want NaN", area)
+	}
+}
+
+func TestCompositeJSON(t *testing.T) {
+	shape := Difference(Rectangle{10, 8}, Place(Circle{1}, Translate(5, 4)))
+	shape.Tolerance = 1e-4
+
+	body, err := MarshalShape(shape)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalShape(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, shape) {
+		t.Errorf("got %#v want %#v", got, shape)
+	}
+	var op BooleanOp
+	if err := json.Unmarshal([]byte(`"xor"`), &op); err == nil {
+		t.Error("expected an unknown operation to be rejected")
+	}
+}
+
+func closeTo(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
+
+func TestCompositeOutlines(t *testing.T) {
+	// A room with a pillar in the middle of it, 4 < x < 6 and 2 < y < 4.
+	room := Difference(Rectangle{10, 6}, Place(Square{2}, Translate(4, 2)))
+	pillar := Place(Circle{0.5}, Translate(5, 3))
+
+	t.Run("outlines", func(t *testing.T) {
+		outlines, err := OutlinesOf(room)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(outlines) != 2 {
+			t.Fatalf("got %d outlines want the walls and the pillar", len(outlines))
+		}
+		var areas []float64
+		for _, o := range outlines {
+			areas = append(areas, Polygon{o.Points}.SignedArea())
+		}
+		sort.Float64s(areas)
+		if !closeTo(areas[0], -4, 1e-9) || !closeTo(areas[1], 60, 1e-9) {
+			t.Errorf("got signed areas %v want the hole clockwise", areas)
+		}
+
+		placed, _ := OutlinesOf(Place(room, Translate(1, 1)))
+		box, _ := BoundsOf(Place(room, Translate(1, 1)))
+		if len(placed) != 2 || box != (Box{Point{1, 1}, Point{11, 7}}) {
+			t.Errorf("got %d outlines in %v", len(placed), box)
+		}
+		if _, err := OutlinesOf(Union(Square{1}, blob{})); !errors.Is(err, ErrUnknownShape) {
+			t.Errorf("got %v want %v", err, ErrUnknownShape)
+		}
+	})
+
+	t.Run("bounds", func(t *testing.T) {
+		got, err := BoundsOf(room)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != (Box{Point{0, 0}, Point{10, 6}}) {
+			t.Errorf("got %v", got)
+		}
+		if _, err := BoundsOf(Intersection(Square{1}, Place(Square{1}, Translate(5, 5)))); !errors.Is(err, ErrInvalidDimension) {
+			t.Errorf("got %v want %v for a composite covering nothing", err, ErrInvalidDimension)
+		}
+	})
+
+	t.Run("contains", func(t *testing.T) {
+		containsTests := []struct {
+			p         Point
+			tolerance float64
+			want      bool
+		}{
+			{Point{1, 1}, 0, true},
+			{Point{5, 3}, 0, false},
+			{Point{4, 3}, epsilon, true},
+			{Point{3.5, 3}, -0.4, true},
+			{Point{3.5, 3}, -0.6, false},
+			{Point{4.2, 3}, 0.3, true},
+		}
+		for _, tt := range containsTests {
+			got, err := ContainsPoint(room, tt.p, tt.tolerance)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("%v within %g: got %t want %t", tt.p, tt.tolerance, got, tt.want)
+			}
+		}
+		if room.Contains(Point{5, 3}) || !room.Contains(Point{9, 5}) {
+			t.Error("got the pillar inside the room")
+		}
+	})
+
+	t.Run("collisions", func(t *testing.T) {
+		if hit, err := Intersects(room, pillar); err != nil || hit {
+			t.Errorf("got %t, %v for a shape in the hole", hit, err)
+		}
+		if hit, _ := Intersects(room, Place(Circle{1.5}, Translate(5, 3))); !hit {
+			t.Error("expected a shape over the hole's edge to collide")
+		}
+		if hit, _ := Intersects(room, Place(Square{20}, Translate(-5, -5))); !hit {
+			t.Error("expected a shape around the room to collide")
+		}
+
+		got, err := Collisions([]Shape{room, pillar, Place(Rectangle{1, 1}, Translate(9.5, 5.5))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []Pair{{0, 2}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("spatial index", func(t *testing.T) {
+		index := NewSpatialIndex()
+		id, err := index.Insert(room)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := index.At(Point{1, 1}); !reflect.DeepEqual(got, []ShapeID{id}) {
+			t.Errorf("got %v in the room", got)
+		}
+		if got := index.At(Point{5, 3}); len(got) != 0 {
+			t.Errorf("got %v in the hole", got)
+		}
+		if got := index.Search(Box{Point{4.5, 2.5}, Point{5.5, 3.5}}); len(got) != 0 {
+			t.Errorf("got %v searching the hole", got)
+		}
+		if got := index.Nearest(Point{5, 3}, 1); len(got) != 1 || !closeTo(got[0].Distance, 1, 1e-9) {
+			t.Errorf("got %v nearest the middle of the hole", got)
+		}
+		if _, err := index.Insert(Intersection(Square{1}, Place(Square{1}, Translate(5, 5)))); !errors.Is(err, ErrInvalidDimension) {
+			t.Errorf("got %v want %v for a composite covering nothing", err, ErrInvalidDimension)
+		}
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := WriteSVG(&out, []Figure{{Shape: room, Style: Style{Fill: "grey", Label: "room"}}}, SVGOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		svg := out.String()
+		if strings.Count(svg, "<path ") != 1 || strings.Count(svg, "Z") != 2 || !strings.Contains(svg, `fill-rule="nonzero"`) {
+			t.Errorf("want the room drawn as one path of two rings, got\n%s", svg)
+		}
+		if !strings.Contains(svg, `viewBox="-0.5 -6.5 11 7"`) {
+			t.Errorf("want the viewBox fitted to the room, got\n%s", svg)
+		}
+	})
+
+	t.Run("raster", func(t *testing.T) {
+		figures := []Figure{{Shape: room, Style: Style{Fill: "blue", Stroke: "none"}}}
+		img, err := Rasterize(figures, RasterOptions{Margin: -0.5, Background: "none"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := img.Bounds().Size(); got != (image.Point{10, 6}) {
+			t.Fatalf("got size %v want 10x6", got)
+		}
+		// Pixel rows run down from the top of the room.
+		for _, p := range []image.Point{{4, 2}, {5, 3}} {
+			if got := img.RGBAAt(p.X, p.Y); got.A != 0 {
+				t.Errorf("pixel %v in the hole: got %v", p, got)
+			}
+		}
+		for _, p := range []image.Point{{0, 0}, {3, 3}, {9, 5}} {
+			if got := img.RGBAAt(p.X, p.Y); got.A != 255 || got.B != 255 {
+				t.Errorf("pixel %v in the room: got %v", p, got)
+			}
+		}
+	})
+}
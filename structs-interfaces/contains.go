@@ -0,0 +1,180 @@
+package structs_interfaces
+
+import "math"
+
+// ContainsPoint reports whether p is inside shape or within tolerance of its
+// boundary. A negative tolerance instead means p must be at least that far
+// inside. Polygons use the non-zero winding rule, so the loops of a
+// self-intersecting polygon count as inside, and shapes with several parts,
+// see OutlinesOf, hold the points their parts wind around.
+func ContainsPoint(shape Shape, p Point, tolerance float64) (bool, error) {
+	r, err := regionOf(shape)
+	if err != nil {
+		return false, err
+	}
+	return r.contains(p, tolerance), nil
+}
+
+// ContainsPoints is ContainsPoint for many points at once. The shape's
+// outline is only worked out once, and points far from its bounding box are
+// rejected without looking at the outline.
+func ContainsPoints(shape Shape, points []Point, tolerance float64) ([]bool, error) {
+	r, err := regionOf(shape)
+	if err != nil {
+		return nil, err
+	}
+	inside := make([]bool, len(points))
+	for i, p := range points {
+		inside[i] = r.contains(p, tolerance)
+	}
+	return inside, nil
+}
+
+func (r Rectangle) Contains(point Point) bool      { return containsPoint(r, point) }
+func (s Square) Contains(point Point) bool         { return containsPoint(s, point) }
+func (c Circle) Contains(point Point) bool         { return containsPoint(c, point) }
+func (e Ellipse) Contains(point Point) bool        { return containsPoint(e, point) }
+func (t Triangle) Contains(point Point) bool       { return containsPoint(t, point) }
+func (t SidedTriangle) Contains(point Point) bool  { return containsPoint(t, point) }
+func (p RegularPolygon) Contains(point Point) bool { return containsPoint(p, point) }
+func (p Polygon) Contains(point Point) bool        { return containsPoint(p, point) }
+func (m MultiPolygon) Contains(point Point) bool   { return containsPoint(m, point) }
+func (c Composite) Contains(point Point) bool      { return containsPoint(c, point) }
+
+// Contains is false for shapes with no known outline.
+func (p Placed) Contains(point Point) bool { return containsPoint(p, point) }
+
+// containsPoint counts points on the boundary as inside, give or take
+// rounding errors.
+func containsPoint(shape Shape, p Point) bool {
+	inside, _ := ContainsPoint(shape, p, epsilon)
+	return inside
+}
+
+func regionOf(shape Shape) (region, error) {
+	outlines, err := OutlinesOf(shape)
+	if err != nil {
+		return region{}, err
+	}
+	return newRegion(outlines), nil
+}
+
+// region is the area inside the outlines of a shape's parts, with what it
+// takes to test points against it.
+type region struct {
+	parts []Outline
+	box   Box
+
+	// For a single curve, the inverse maps back to the unit circle, and
+	// distances there are stretched by between minor and major going back
+	// again.
+	inverse      Matrix
+	invertible   bool
+	major, minor float64
+}
+
+// newRegion keeps a single curve exact, but stands polygons in for curves
+// among several parts so the parts can be wound around together.
+func newRegion(parts []Outline) region {
+	r := region{parts: parts}
+	if len(parts) == 1 && parts[0].Curved {
+		curve := parts[0].Curve
+		inverse, err := curve.Invert()
+		r.inverse, r.invertible = inverse, err == nil
+		r.major, r.minor = curve.Stretch()
+	} else {
+		r.parts = make([]Outline, len(parts))
+		for i, part := range parts {
+			if part.Curved {
+				part = Outline{Points: curvePolygon(part.Curve, DefaultTolerance)}
+			}
+			r.parts[i] = part
+		}
+	}
+	for i, part := range r.parts {
+		if i == 0 {
+			r.box = part.Bounds()
+		} else {
+			r.box = r.box.Union(part.Bounds())
+		}
+	}
+	return r
+}
+
+// curve reports whether the region is a single curve.
+func (r region) curve() bool {
+	return len(r.parts) == 1 && r.parts[0].Curved
+}
+
+func (r region) contains(p Point, tolerance float64) bool {
+	if len(r.parts) == 0 || boxDistance(r.box, p) > math.Max(tolerance, 0) {
+		return false
+	}
+	if r.curve() && r.invertible {
+		// The unit circle's boundary is 1-|q| away from q, so the real
+		// distance is within a factor of the stretch of that. Only work it
+		// out exactly if the bounds don't settle it.
+		q := r.inverse.Apply(p)
+		s := math.Hypot(q.X, q.Y) - 1
+		lo, hi := s*r.minor, s*r.major
+		if s < 0 {
+			lo, hi = hi, lo
+		}
+		if hi <= tolerance {
+			return true
+		}
+		if lo > tolerance {
+			return false
+		}
+	}
+	return r.signedDistance(p) <= tolerance
+}
+
+// signedDistance is the distance from p to the boundary, negative inside.
+func (r region) signedDistance(p Point) float64 {
+	if r.curve() {
+		curve := r.parts[0].Curve
+		if curve.scalesEvenly() {
+			radius, _ := curve.Stretch()
+			return distance(p, Point{curve.E, curve.F}) - radius
+		}
+		d := closestOnCurve(p, curve)
+		if r.invertible {
+			q := r.inverse.Apply(p)
+			if q.X*q.X+q.Y*q.Y < 1 {
+				return -d
+			}
+		}
+		return d
+	}
+
+	d, winding := math.Inf(1), 0
+	for _, part := range r.parts {
+		points := part.Points
+		for i, a := range points {
+			d = math.Min(d, distanceToSegment(p, a, points[(i+1)%len(points)]))
+		}
+		winding += windingNumber(p, points)
+	}
+	if winding != 0 {
+		return -d
+	}
+	return d
+}
+
+// windingNumber counts how many times the polygon goes around p,
+// counter-clockwise turns counting as positive.
+func windingNumber(p Point, points []Point) int {
+	winding := 0
+	for i, a := range points {
+		b := points[(i+1)%len(points)]
+		if a.Y <= p.Y {
+			if b.Y > p.Y && cross(a, b, p) > 0 {
+				winding++
+			}
+		} else if b.Y <= p.Y && cross(a, b, p) < 0 {
+			winding--
+		}
+	}
+	return winding
+}
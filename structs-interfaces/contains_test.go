@@ -0,0 +1,153 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestContains(t *testing.T) {
+	containsTests := []struct {
+		shape interface {
+			Shape
+			Contains(Point) bool
+		}
+		point Point
+		want  bool
+	}{
+		{Rectangle{4, 2}, Point{1, 1}, true},
+		{Rectangle{4, 2}, Point{4, 2}, true},
+		{Rectangle{4, 2}, Point{4.1, 1}, false},
+		{Square{2}, Point{1, 2}, true},
+		{Circle{2}, Point{0, -2}, true},
+		{Circle{2}, Point{1.5, 1.5}, false},
+		{Ellipse{3, 1}, Point{2.9, 0}, true},
+		{Ellipse{3, 1}, Point{2, 0.8}, false},
+		{Triangle{4, 3}, Point{2, 2.9}, true},
+		{Triangle{4, 3}, Point{0.5, 2}, false},
+		{SidedTriangle{3, 4, 5}, Point{1, 1}, true},
+		{RegularPolygon{6, 1}, Point{0, 0}, true},
+		{Polygon{lShape}, Point{0.5, 2.5}, true},
+		{Polygon{lShape}, Point{2, 2}, false},
+		{Polygon{lShape}, Point{1, 2}, true},
+		{MultiPolygon{[]Polygon{{square}, {lShape}}}, Point{3.5, 0.5}, true},
+		{Place(Rectangle{4, 2}, Rotate(math.Pi/2)), Point{-1, 3}, true},
+		{Place(Rectangle{4, 2}, Rotate(math.Pi/2)), Point{1, 3}, false},
+		{Place(Circle{1}, Scale(3, 1).Then(Rotate(math.Pi/4))), Point{2, 2}, true},
+	}
+
+	for _, tt := range containsTests {
+		if got := tt.shape.Contains(tt.point); got != tt.want {
+			t.Errorf("%#v contains %v: got %t want %t", tt.shape, tt.point, got, tt.want)
+		}
+	}
+}
+
+func TestContainsPointTolerance(t *testing.T) {
+	shapes := []Shape{Rectangle{2, 2}, Polygon{[]Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}}}}
+	for _, shape := range shapes {
+		cases := []struct {
+			point     Point
+			tolerance float64
+			want      bool
+		}{
+			{Point{2, 1}, 0, true},
+			{Point{2.05, 1}, 0, false},
+			{Point{2.05, 1}, 0.1, true},
+			{Point{2.05, 2.05}, 0.1, true},
+			{Point{2.1, 2.1}, 0.1, false},
+			{Point{2, 1}, -0.1, false},
+			{Point{1.95, 1}, -0.1, false},
+			{Point{1.85, 1}, -0.1, true},
+		}
+
+		for _, c := range cases {
+			got, err := ContainsPoint(shape, c.point, c.tolerance)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("%#v contains %v within %g: got %t want %t", shape, c.point, c.tolerance, got, c.want)
+			}
+		}
+	}
+
+	t.Run("curves", func(t *testing.T) {
+		ellipse := Place(Ellipse{3, 1}, Rotate(0.3))
+		for _, c := range []struct {
+			point     Point
+			tolerance float64
+			want      bool
+		}{
+			{Rotate(0.3).Apply(Point{0, 1.05}), 0.1, true},
+			{Rotate(0.3).Apply(Point{0, 1.15}), 0.1, false},
+			{Rotate(0.3).Apply(Point{2.9, 0}), -0.05, true},
+			{Rotate(0.3).Apply(Point{2.9, 0}), -0.2, false},
+		} {
+			got, _ := ContainsPoint(ellipse, c.point, c.tolerance)
+			if got != c.want {
+				t.Errorf("contains %v within %g: got %t want %t", c.point, c.tolerance, got, c.want)
+			}
+		}
+	})
+
+	t.Run("winding", func(t *testing.T) {
+		// Going round twice leaves the centre wound twice, which even-odd
+		// ray casting would call outside.
+		twice := Polygon{[]Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0.1}, {2.1, 0.1}, {2.1, 2.1}, {0.1, 2.1}}}
+		if !twice.Contains(Point{1, 1}) {
+			t.Error("expected a point wound around twice to be inside")
+		}
+	})
+
+	t.Run("unknown shapes", func(t *testing.T) {
+		if _, err := ContainsPoint(blob{}, Point{}, 0); !errors.Is(err, ErrUnknownShape) {
+			t.Errorf("got %v want %v", err, ErrUnknownShape)
+		}
+		if (Placed{blob{}, Identity}).Contains(Point{}) {
+			t.Error("expected an unknown shape to contain nothing")
+		}
+	})
+}
+
+func TestContainsPoints(t *testing.T) {
+	random := rand.New(rand.NewSource(5))
+	shapes := []Shape{
+		Place(Polygon{lShape}, Rotate(0.5)),
+		Place(Circle{2}, Scale(1, 0.5).Then(Translate(1, 1))),
+		MultiPolygon{[]Polygon{{square}, {lShape}}},
+	}
+	points := make([]Point, 500)
+	for i := range points {
+		points[i] = Point{random.Float64()*8 - 4, random.Float64()*8 - 4}
+	}
+
+	for _, shape := range shapes {
+		got, err := ContainsPoints(shape, points, 0.01)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := make([]bool, len(points))
+		for i, p := range points {
+			want[i], _ = ContainsPoint(shape, p, 0.01)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%#v: batch and single results differ", shape)
+		}
+	}
+}
+
+func BenchmarkContainsPoints(b *testing.B) {
+	random := rand.New(rand.NewSource(6))
+	shape := Place(Ellipse{3, 1}, Rotate(0.4))
+	points := make([]Point, 1000)
+	for i := range points {
+		points[i] = Point{random.Float64()*8 - 4, random.Float64()*8 - 4}
+	}
+
+	for i := 0; i < b.N; i++ {
+		ContainsPoints(shape, points, 0.01)
+	}
+}
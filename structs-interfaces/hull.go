@@ -0,0 +1,197 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+var ErrNoPoints = errors.New("no points given")
+
+// ConvexHull returns the smallest convex polygon holding every point, counter-
+// clockwise from the lowest, leftmost point. Duplicate points and points along
+// the hull's edges are left out. Points that all lie on one line have no hull
+// and give ErrDegeneratePolygon.
+func ConvexHull(points []Point) (Polygon, error) {
+	if len(points) == 0 {
+		return Polygon{}, ErrNoPoints
+	}
+	sorted := append([]Point(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Y != sorted[j].Y {
+			return sorted[i].Y < sorted[j].Y
+		}
+		return sorted[i].X < sorted[j].X
+	})
+
+	// Andrew's monotone chain: the right side going up, then the left side
+	// coming back down, popping any point that does not turn left.
+	hull := make([]Point, 0, 2*len(sorted))
+	for _, chain := range [][]Point{sorted, reversed(sorted)} {
+		start := len(hull)
+		for _, p := range chain {
+			for len(hull) >= start+2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		hull = hull[:len(hull)-1]
+	}
+
+	polygon := Polygon{hull}
+	if len(hull) < 3 || polygon.Orientation() == Collinear {
+		return Polygon{}, ErrDegeneratePolygon
+	}
+	return polygon, nil
+}
+
+// MinimumEnclosingCircle returns the smallest circle holding every point,
+// placed on its centre, found with Welzl's algorithm. Points all in one place
+// give a circle of zero radius there, which holds them but is not a valid
+// shape.
+func MinimumEnclosingCircle(points []Point) (Placed, error) {
+	if len(points) == 0 {
+		return Placed{}, ErrNoPoints
+	}
+	shuffled := append([]Point(nil), points...)
+	random := rand.New(rand.NewSource(int64(len(points))))
+	random.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	c := enclosing{shuffled[0], 0}
+	for i, p := range shuffled {
+		if c.holds(p) {
+			continue
+		}
+		c = enclosing{p, 0}
+		for j, q := range shuffled[:i] {
+			if c.holds(q) {
+				continue
+			}
+			c = enclosingTwo(p, q)
+			for _, r := range shuffled[:j] {
+				if !c.holds(r) {
+					c = enclosingThree(p, q, r)
+				}
+			}
+		}
+	}
+
+	return Place(Circle{c.radius}, Translate(c.centre.X, c.centre.Y)), nil
+}
+
+type enclosing struct {
+	centre Point
+	radius float64
+}
+
+func (c enclosing) holds(p Point) bool {
+	return distance(c.centre, p) <= c.radius+epsilon*math.Max(1, c.radius)
+}
+
+func enclosingTwo(a, b Point) enclosing {
+	centre := Point{(a.X + b.X) / 2, (a.Y + b.Y) / 2}
+	return enclosing{centre, distance(centre, a)}
+}
+
+// enclosingThree is the circle through a, b and c, or for points in a line
+// the circle across the two furthest apart.
+func enclosingThree(a, b, c Point) enclosing {
+	d := 2 * cross(a, b, c)
+	if math.Abs(d) < epsilon {
+		best := enclosingTwo(a, b)
+		for _, e := range []enclosing{enclosingTwo(a, c), enclosingTwo(b, c)} {
+			if e.radius > best.radius {
+				best = e
+			}
+		}
+		return best
+	}
+	ab, ac := sub(b, a), sub(c, a)
+	lb, lc := dot(ab, ab), dot(ac, ac)
+	centre := Point{a.X + (ac.Y*lb-ab.Y*lc)/d, a.Y + (ab.X*lc-ac.X*lb)/d}
+	return enclosing{centre, math.Max(distance(centre, a), math.Max(distance(centre, b), distance(centre, c)))}
+}
+
+// MinimumAreaRectangle returns the smallest rectangle holding every point,
+// placed where the points are. One of its sides always lies along an edge of
+// the convex hull, so rotating calipers try each edge in turn while tracking
+// the hull points furthest along, across and back from it. Points in a line
+// have no hull and give a rectangle of zero height along it instead, which is
+// not a valid shape.
+func MinimumAreaRectangle(points []Point) (Placed, error) {
+	hull, err := ConvexHull(points)
+	if errors.Is(err, ErrDegeneratePolygon) {
+		return spanningRectangle(points), nil
+	}
+	if err != nil {
+		return Placed{}, err
+	}
+	p := hull.Points
+	n := len(p)
+	at := func(i int) Point { return p[i%n] }
+
+	var best Placed
+	bestArea := math.Inf(1)
+	far, top, back := 1, 1, 1
+	for i := 0; i < n; i++ {
+		edge := sub(at(i+1), at(i))
+		length := math.Hypot(edge.X, edge.Y)
+		u := Point{edge.X / length, edge.Y / length}
+		v := Point{-u.Y, u.X}
+
+		along := func(k int) float64 { return dot(sub(at(k), at(i)), u) }
+		across := func(k int) float64 { return dot(sub(at(k), at(i)), v) }
+		if far < i+1 {
+			far = i + 1
+		}
+		for along(far+1) >= along(far) && far < i+n {
+			far++
+		}
+		if top < far {
+			top = far
+		}
+		for across(top+1) >= across(top) && top < i+n {
+			top++
+		}
+		if back < top {
+			back = top
+		}
+		for along(back+1) <= along(back) && back < i+n {
+			back++
+		}
+
+		width, height := along(far)-along(back), across(top)
+		if area := width * height; area < bestArea {
+			corner := at(i)
+			corner = Point{corner.X + u.X*along(back), corner.Y + u.Y*along(back)}
+			bestArea = area
+			best = Place(Rectangle{width, height}, Rotate(math.Atan2(u.Y, u.X)).Then(Translate(corner.X, corner.Y)))
+		}
+	}
+	return best, nil
+}
+
+// spanningRectangle is the rectangle of zero height between the ends of a
+// line of points. The point furthest from any of them is at one end, and the
+// point furthest from that is at the other.
+func spanningRectangle(points []Point) Placed {
+	furthest := func(from Point) Point {
+		to := from
+		for _, p := range points {
+			if distance(from, p) > distance(from, to) {
+				to = p
+			}
+		}
+		return to
+	}
+	from := furthest(points[0])
+	edge := sub(furthest(from), from)
+	return Place(Rectangle{math.Hypot(edge.X, edge.Y), 0}, Rotate(math.Atan2(edge.Y, edge.X)).Then(Translate(from.X, from.Y)))
+}
+
+func reversed(points []Point) []Point {
+	out := append([]Point(nil), points...)
+	reverse(out)
+	return out
+}
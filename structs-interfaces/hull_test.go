@@ -0,0 +1,263 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestConvexHull(t *testing.T) {
+	t.Run("duplicates and collinear points", func(t *testing.T) {
+		points := []Point{{0, 0}, {2, 0}, {1, 0}, {2, 2}, {0, 2}, {2, 2}, {1, 1}, {0, 1}, {0, 0}}
+
+		hull, err := ConvexHull(points)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+		if !reflect.DeepEqual(hull.Points, want) {
+			t.Errorf("got %v want %v", hull.Points, want)
+		}
+	})
+
+	t.Run("no hull", func(t *testing.T) {
+		cases := map[string][]Point{
+			"one point":      {{1, 1}},
+			"same point":     {{1, 1}, {1, 1}, {1, 1}},
+			"points in line": {{0, 0}, {1, 1}, {3, 3}, {2, 2}},
+		}
+		for name, points := range cases {
+			if _, err := ConvexHull(points); !errors.Is(err, ErrDegeneratePolygon) {
+				t.Errorf("%s: got %v want %v", name, err, ErrDegeneratePolygon)
+			}
+		}
+		if _, err := ConvexHull(nil); !errors.Is(err, ErrNoPoints) {
+			t.Errorf("got %v want %v", err, ErrNoPoints)
+		}
+	})
+
+	t.Run("random points", func(t *testing.T) {
+		random := rand.New(rand.NewSource(8))
+		for i := 0; i < 100; i++ {
+			points := randomPoints(random, 3+random.Intn(200))
+
+			hull, err := ConvexHull(points)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !hull.IsConvex() || hull.Orientation() != CounterClockwise {
+				t.Fatalf("hull %v is not convex and counter-clockwise", hull.Points)
+			}
+			for _, p := range points {
+				if !hull.Contains(p) {
+					t.Fatalf("hull %v does not hold %v", hull.Points, p)
+				}
+			}
+			for _, corner := range hull.Points {
+				if !containsExact(points, corner) {
+					t.Fatalf("hull corner %v is not one of the points", corner)
+				}
+			}
+		}
+	})
+}
+
+func TestMinimumEnclosingCircle(t *testing.T) {
+	t.Run("known circles", func(t *testing.T) {
+		cases := []struct {
+			points []Point
+			centre Point
+			radius float64
+		}{
+			{[]Point{{0, 0}, {4, 0}}, Point{2, 0}, 2},
+			{[]Point{{0, 0}, {4, 0}, {2, 1}}, Point{2, 0}, 2},
+			{[]Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {1, 0}}, Point{1.5, 0}, 1.5},
+			{square, Point{2, 2}, math.Sqrt(8)},
+		}
+
+		for _, c := range cases {
+			circle, err := MinimumEnclosingCircle(c.points)
+			if err != nil {
+				t.Fatal(err)
+			}
+			outline, _ := OutlineOf(circle)
+			radius, _ := outline.Curve.Stretch()
+			assertPoint(t, Point{outline.Curve.E, outline.Curve.F}, c.centre)
+			if !almostEqual(radius, c.radius) {
+				t.Errorf("%v: got radius %g want %g", c.points, radius, c.radius)
+			}
+		}
+	})
+
+	t.Run("random points against brute force", func(t *testing.T) {
+		random := rand.New(rand.NewSource(9))
+		for i := 0; i < 50; i++ {
+			points := randomPoints(random, 2+random.Intn(15))
+
+			circle, err := MinimumEnclosingCircle(points)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range points {
+				if ok, _ := ContainsPoint(circle, p, 1e-9); !ok {
+					t.Fatalf("%v does not hold %v", circle, p)
+				}
+			}
+			if got, want := circle.Shape.(Circle).Radius, bruteEnclosingRadius(points); !almostEqual(got, want) {
+				t.Errorf("got radius %g want %g", got, want)
+			}
+		}
+	})
+
+	t.Run("no circle", func(t *testing.T) {
+		if _, err := MinimumEnclosingCircle(nil); !errors.Is(err, ErrNoPoints) {
+			t.Errorf("got %v want %v", err, ErrNoPoints)
+		}
+	})
+
+	t.Run("points in one place", func(t *testing.T) {
+		circle, err := MinimumEnclosingCircle([]Point{{1, 2}, {1, 2}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if circle.Shape != (Circle{0}) {
+			t.Errorf("got %v want a circle of zero radius", circle)
+		}
+		assertPoint(t, circle.Transform.Apply(Point{}), Point{1, 2})
+		if ok, _ := ContainsPoint(circle, Point{1, 2}, 1e-9); !ok {
+			t.Error("expected the circle to hold the points")
+		}
+		if err := circle.Validate(); !errors.Is(err, ErrInvalidDimension) {
+			t.Errorf("got %v want %v", err, ErrInvalidDimension)
+		}
+	})
+}
+
+func TestMinimumAreaRectangle(t *testing.T) {
+	t.Run("rotated rectangle", func(t *testing.T) {
+		m := Rotate(0.6).Then(Translate(3, -1))
+		var points []Point
+		for _, p := range []Point{{0, 0}, {5, 0}, {5, 2}, {0, 2}, {2, 1}, {5, 1}, {1, 0}} {
+			points = append(points, m.Apply(p))
+		}
+
+		rectangle, err := MinimumAreaRectangle(points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !almostEqual(rectangle.Area(), 10) {
+			t.Errorf("got area %g want 10", rectangle.Area())
+		}
+	})
+
+	t.Run("random points against brute force", func(t *testing.T) {
+		random := rand.New(rand.NewSource(10))
+		for i := 0; i < 100; i++ {
+			stretch := Scale(1, random.Float64()).Then(Rotate(random.Float64() * math.Pi))
+			points := randomPoints(random, 3+random.Intn(100))
+			for j, p := range points {
+				points[j] = stretch.Apply(p)
+			}
+
+			rectangle, err := MinimumAreaRectangle(points)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range points {
+				if ok, _ := ContainsPoint(rectangle, p, 1e-9); !ok {
+					t.Fatalf("%v does not hold %v", rectangle, p)
+				}
+			}
+			if got, want := rectangle.Area(), bruteRectangleArea(points); !almostEqual(got, want) {
+				t.Errorf("got area %g want %g", got, want)
+			}
+		}
+	})
+
+	t.Run("points in line", func(t *testing.T) {
+		points := []Point{{1, 1}, {0, 0}, {2, 2}, {1, 1}}
+		rectangle, err := MinimumAreaRectangle(points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rectangle.Area() != 0 || !almostEqual(rectangle.Perimeter(), 4*math.Sqrt2) {
+			t.Errorf("got area %g perimeter %g want a rectangle of zero height", rectangle.Area(), rectangle.Perimeter())
+		}
+		for _, p := range points {
+			if ok, _ := ContainsPoint(rectangle, p, 1e-9); !ok {
+				t.Errorf("%v does not hold %v", rectangle, p)
+			}
+		}
+		if err := rectangle.Validate(); !errors.Is(err, ErrInvalidDimension) {
+			t.Errorf("got %v want %v", err, ErrInvalidDimension)
+		}
+
+		single, err := MinimumAreaRectangle([]Point{{3, 4}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPoint(t, single.Transform.Apply(Point{}), Point{3, 4})
+		if _, err := MinimumAreaRectangle(nil); !errors.Is(err, ErrNoPoints) {
+			t.Errorf("got %v want %v", err, ErrNoPoints)
+		}
+	})
+}
+
+// randomPoints are on a coarse grid, so duplicates and collinear points turn
+// up often.
+func randomPoints(random *rand.Rand, n int) []Point {
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{float64(random.Intn(20)), float64(random.Intn(20))}
+	}
+	points = append(points, Point{0, 0}, Point{19, 0}, Point{0, 19})
+	return points
+}
+
+func containsExact(points []Point, p Point) bool {
+	for _, q := range points {
+		if p == q {
+			return true
+		}
+	}
+	return false
+}
+
+// bruteEnclosingRadius tries every circle through two or three of the points.
+func bruteEnclosingRadius(points []Point) float64 {
+	best := math.Inf(1)
+	try := func(c enclosing) {
+		for _, p := range points {
+			if !c.holds(p) {
+				return
+			}
+		}
+		best = math.Min(best, c.radius)
+	}
+	for i := range points {
+		for j := i + 1; j < len(points); j++ {
+			try(enclosingTwo(points[i], points[j]))
+			for k := j + 1; k < len(points); k++ {
+				try(enclosingThree(points[i], points[j], points[k]))
+			}
+		}
+	}
+	return best
+}
+
+// bruteRectangleArea tries a rectangle along every edge of the hull.
+func bruteRectangleArea(points []Point) float64 {
+	hull, _ := ConvexHull(points)
+	best := math.Inf(1)
+	for i, a := range hull.Points {
+		edge := sub(hull.Points[(i+1)%len(hull.Points)], a)
+		angle := math.Atan2(edge.Y, edge.X)
+		box := Outline{Points: points}.Transform(Rotate(-angle)).Bounds()
+		best = math.Min(best, box.Area())
+	}
+	return best
+}
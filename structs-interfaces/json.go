@@ -0,0 +1,255 @@
+package structs_interfaces
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUnknownShapeType   = errors.New("unknown shape type")
+	ErrDuplicateShapeType = errors.New("shape type is already registered")
+	ErrMissingField       = errors.New("shape is missing a field")
+	ErrUnknownField       = errors.New("shape has an unknown field")
+)
+
+// shapeTypes maps the "type" written into JSON to the Go type it decodes to.
+var shapeTypes = newShapeRegistry(map[string]Shape{
+	"rectangle":       Rectangle{},
+	"square":          Square{},
+	"circle":          Circle{},
+	"ellipse":         Ellipse{},
+	"triangle":        Triangle{},
+	"sided_triangle":  SidedTriangle{},
+	"regular_polygon": RegularPolygon{},
+	"polygon":         Polygon{},
+	"multi_polygon":   MultiPolygon{},
+	"placed":          Placed{},
+	"composite":       Composite{},
+})
+
+type shapeRegistry struct {
+	mu     sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}
+
+func newShapeRegistry(shapes map[string]Shape) *shapeRegistry {
+	r := &shapeRegistry{byName: map[string]reflect.Type{}, byType: map[reflect.Type]string{}}
+	for name, shape := range shapes {
+		r.byName[name] = reflect.TypeOf(shape)
+		r.byType[reflect.TypeOf(shape)] = name
+	}
+	return r
+}
+
+// RegisterShape lets shapes of example's type be encoded and decoded under
+// name. Fields are matched using the usual encoding/json rules, and every
+// field not tagged omitempty must be present when decoding.
+func RegisterShape(name string, example Shape) error {
+	if name == "" || example == nil {
+		return fmt.Errorf("%w: a shape type needs a name and an example", ErrUnknownShapeType)
+	}
+	typ := reflect.TypeOf(example)
+
+	shapeTypes.mu.Lock()
+	defer shapeTypes.mu.Unlock()
+	if _, exists := shapeTypes.byName[name]; exists {
+		return fmt.Errorf("%w: %q", ErrDuplicateShapeType, name)
+	}
+	if existing, exists := shapeTypes.byType[typ]; exists {
+		return fmt.Errorf("%w: %v is registered as %q", ErrDuplicateShapeType, typ, existing)
+	}
+	shapeTypes.byName[name] = typ
+	shapeTypes.byType[typ] = name
+	return nil
+}
+
+func (r *shapeRegistry) name(typ reflect.Type) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	name, ok := r.byType[typ]
+	return name, ok
+}
+
+func (r *shapeRegistry) lookup(name string) (reflect.Type, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	typ, ok := r.byName[name]
+	return typ, ok
+}
+
+// MarshalShape encodes shape as a JSON object whose "type" says which shape
+// it is, e.g. {"type":"circle","radius":2}.
+func MarshalShape(shape Shape) ([]byte, error) {
+	name, ok := shapeTypes.name(reflect.TypeOf(shape))
+	if !ok {
+		return nil, fmt.Errorf("%w: %T is not registered", ErrUnknownShapeType, shape)
+	}
+	body, err := json.Marshal(shape)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < 2 || body[0] != '{' {
+		return nil, fmt.Errorf("%s: shapes must encode as JSON objects, got %s", name, body)
+	}
+
+	var out bytes.Buffer
+	out.WriteString(`{"type":`)
+	quoted, _ := json.Marshal(name)
+	out.Write(quoted)
+	if len(body) > 2 {
+		out.WriteByte(',')
+	}
+	out.Write(body[1:])
+	return out.Bytes(), nil
+}
+
+// UnmarshalShape decodes a shape written by MarshalShape. The type must be
+// registered, every field must be present and known, and the shape must pass
+// Validate.
+func UnmarshalShape(data []byte) (Shape, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return nil, fmt.Errorf("%w: shape is null", ErrMissingField)
+	}
+
+	raw, ok := fields["type"]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrMissingField, "type")
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return nil, fmt.Errorf("shape type must be a string, got %s", raw)
+	}
+	typ, ok := shapeTypes.lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownShapeType, name)
+	}
+	delete(fields, "type")
+	if err := checkFields(name, typ, fields); err != nil {
+		return nil, err
+	}
+
+	value := reflect.New(typ)
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	shape := value.Elem().Interface().(Shape)
+	if err := Validate(shape); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return shape, nil
+}
+
+// checkFields compares the keys of a JSON object with the fields of the
+// struct it will be decoded into.
+func checkFields(name string, typ reflect.Type, fields map[string]json.RawMessage) error {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	known := map[string]bool{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		key, options := field.Name, ""
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			parts := strings.SplitN(tag, ",", 2)
+			if parts[0] != "" {
+				key = parts[0]
+			}
+			if len(parts) > 1 {
+				options = parts[1]
+			}
+		}
+		known[key] = true
+		if _, ok := fields[key]; !ok && !strings.Contains(options, "omitempty") {
+			return fmt.Errorf("%w: %s needs %q", ErrMissingField, name, key)
+		}
+	}
+
+	for key := range fields {
+		if !known[key] {
+			return fmt.Errorf("%w: %s has no field %q", ErrUnknownField, name, key)
+		}
+	}
+	return nil
+}
+
+func (p Placed) MarshalJSON() ([]byte, error) {
+	shape, err := MarshalShape(p.Shape)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Shape     json.RawMessage `json:"shape"`
+		Transform Matrix          `json:"transform"`
+	}{shape, p.Transform})
+}
+
+func (p *Placed) UnmarshalJSON(data []byte) error {
+	var fields struct {
+		Shape     json.RawMessage `json:"shape"`
+		Transform Matrix          `json:"transform"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if fields.Shape == nil {
+		return fmt.Errorf("%w: placed needs %q", ErrMissingField, "shape")
+	}
+	shape, err := UnmarshalShape(fields.Shape)
+	if err != nil {
+		return err
+	}
+	p.Shape, p.Transform = shape, fields.Transform
+	return nil
+}
+
+// Shapes is a list of shapes that encodes each one with its type.
+type Shapes []Shape
+
+func (s Shapes) MarshalJSON() ([]byte, error) {
+	encoded := make([]json.RawMessage, len(s))
+	for i, shape := range s {
+		body, err := MarshalShape(shape)
+		if err != nil {
+			return nil, fmt.Errorf("shape %d: %w", i, err)
+		}
+		encoded[i] = body
+	}
+	return json.Marshal(encoded)
+}
+
+func (s *Shapes) UnmarshalJSON(data []byte) error {
+	var encoded []json.RawMessage
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	shapes := make(Shapes, len(encoded))
+	for i, body := range encoded {
+		shape, err := UnmarshalShape(body)
+		if err != nil {
+			return fmt.Errorf("shape %d: %w", i, err)
+		}
+		shapes[i] = shape
+	}
+	*s = shapes
+	return nil
+}
@@ -0,0 +1,139 @@
+package structs_interfaces
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type star struct {
+	Points int     `json:"points"`
+	Radius float64 `json:"radius"`
+	Note   string  `json:"note,omitempty"`
+}
+
+func (s star) Area() float64      { return float64(s.Points) * s.Radius * s.Radius / 4 }
+func (s star) Perimeter() float64 { return 2 * float64(s.Points) * s.Radius }
+
+// Registering happens once per test binary, so -count=2 still passes.
+var registerStar = RegisterShape("star", star{})
+
+func TestShapeJSON(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		shapes := Shapes{
+			Rectangle{4, 2},
+			Square{3},
+			Circle{2},
+			Ellipse{3, 1},
+			Triangle{4, 3},
+			SidedTriangle{3, 4, 5},
+			RegularPolygon{5, 2},
+			Polygon{lShape},
+			Place(Circle{1}, Scale(2, 1).Then(Rotate(math.Pi/3)).Then(Translate(1, 2))),
+		}
+
+		body, err := json.Marshal(shapes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Shapes
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(got, shapes) {
+			t.Errorf("got %#v want %#v", got, shapes)
+		}
+	})
+
+	t.Run("type comes first", func(t *testing.T) {
+		body, err := MarshalShape(Circle{2})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := string(body), `{"type":"circle","radius":2}`; got != want {
+			t.Errorf("got %s want %s", got, want)
+		}
+	})
+
+	t.Run("nested placed shape", func(t *testing.T) {
+		body := `{"type":"placed","shape":{"type":"square","side":2},"transform":{"a":1,"b":0,"c":0,"d":1,"e":3,"f":4}}`
+
+		shape, err := UnmarshalShape([]byte(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := Place(Square{2}, Translate(3, 4))
+		if !reflect.DeepEqual(shape, want) {
+			t.Errorf("got %#v want %#v", shape, want)
+		}
+	})
+
+	t.Run("registered shapes", func(t *testing.T) {
+		if registerStar != nil {
+			t.Fatal(registerStar)
+		}
+
+		body, err := MarshalShape(star{Points: 5, Radius: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		shape, err := UnmarshalShape(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shape != (star{Points: 5, Radius: 2}) {
+			t.Errorf("got %#v", shape)
+		}
+
+		if err := RegisterShape("star", Circle{}); !errors.Is(err, ErrDuplicateShapeType) {
+			t.Errorf("got %v want %v", err, ErrDuplicateShapeType)
+		}
+		if err := RegisterShape("round", Circle{}); !errors.Is(err, ErrDuplicateShapeType) {
+			t.Errorf("got %v want %v", err, ErrDuplicateShapeType)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		cases := []struct {
+			name string
+			body string
+			want error
+		}{
+			{"unknown type", `{"type":"hexagon","side":1}`, ErrUnknownShapeType},
+			{"no type", `{"radius":1}`, ErrMissingField},
+			{"missing field", `{"type":"rectangle","width":1}`, ErrMissingField},
+			{"unknown field", `{"type":"circle","radius":1,"colour":"red"}`, ErrUnknownField},
+			{"invalid dimension", `{"type":"circle","radius":-1}`, ErrInvalidDimension},
+			{"missing nested field", `{"type":"placed","shape":{"type":"square"},"transform":{"a":1,"b":0,"c":0,"d":1,"e":0,"f":0}}`, ErrMissingField},
+			{"missing transform", `{"type":"placed","shape":{"type":"square","side":1}}`, ErrMissingField},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				_, err := UnmarshalShape([]byte(c.body))
+
+				if !errors.Is(err, c.want) {
+					t.Errorf("got %v want %v", err, c.want)
+				}
+			})
+		}
+
+		if _, err := MarshalShape(blob{}); !errors.Is(err, ErrUnknownShapeType) {
+			t.Errorf("got %v want %v", err, ErrUnknownShapeType)
+		}
+	})
+
+	t.Run("errors say which shape in a list failed", func(t *testing.T) {
+		var shapes Shapes
+		err := json.Unmarshal([]byte(`[{"type":"square","side":1},{"type":"circle"}]`), &shapes)
+
+		if got, want := err.Error(), `shape 1: shape is missing a field: circle needs "radius"`; got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package structs_interfaces
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+type PackOptions struct {
+	// AllowRotation lets items be turned 90° when that fits them better.
+	AllowRotation bool
+}
+
+// PackedItem is where one of the items went. Shape is the item as a
+// Rectangle placed in the container's coordinates, with its width and height
+// swapped if it was rotated.
+type PackedItem struct {
+	Index   int
+	Shape   Placed
+	Rotated bool
+}
+
+type Packing struct {
+	Packed []PackedItem
+	// Unplaced holds the indexes of items that did not fit, in order.
+	Unplaced []int
+	// Utilization is the fraction of the container covered by items.
+	Utilization float64
+}
+
+// Shapes returns the packed items, ready to draw or test for overlaps.
+func (p Packing) Shapes() []Shape {
+	shapes := make([]Shape, len(p.Packed))
+	for i, item := range p.Packed {
+		shapes[i] = item.Shape
+	}
+	return shapes
+}
+
+// Pack places items inside container, which has its corner on the origin,
+// without overlapping. It uses the MaxRects algorithm: it keeps every largest
+// empty rectangle left in the container, and puts each item, biggest first,
+// in the one that leaves the shortest leftover side.
+func Pack(container Rectangle, items []Rectangle, options PackOptions) (Packing, error) {
+	if err := container.Validate(); err != nil {
+		return Packing{}, fmt.Errorf("container: %w", err)
+	}
+	order := make([]int, len(items))
+	for i, item := range items {
+		if err := item.Validate(); err != nil {
+			return Packing{}, fmt.Errorf("item %d: %w", i, err)
+		}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return items[order[a]].Area() > items[order[b]].Area()
+	})
+
+	free := []Box{{Point{0, 0}, Point{container.Width, container.Height}}}
+	var packing Packing
+	var used float64
+	for _, i := range order {
+		spot, rotated, ok := bestFit(free, items[i], options.AllowRotation)
+		if !ok {
+			packing.Unplaced = append(packing.Unplaced, i)
+			continue
+		}
+		width, height := items[i].Width, items[i].Height
+		if rotated {
+			width, height = height, width
+		}
+		placed := Box{spot, Point{spot.X + width, spot.Y + height}}
+		free = splitFree(free, placed)
+
+		packing.Packed = append(packing.Packed, PackedItem{i, Place(Rectangle{width, height}, Translate(spot.X, spot.Y)), rotated})
+		used += items[i].Area()
+	}
+
+	sort.Ints(packing.Unplaced)
+	sort.Slice(packing.Packed, func(a, b int) bool { return packing.Packed[a].Index < packing.Packed[b].Index })
+	packing.Utilization = used / container.Area()
+	return packing, nil
+}
+
+// bestFit finds the free rectangle that fits item leaving the shortest
+// leftover side, then the shortest longer side.
+func bestFit(free []Box, item Rectangle, rotate bool) (Point, bool, bool) {
+	var spot Point
+	var rotated, found bool
+	bestShort, bestLong := math.Inf(1), math.Inf(1)
+	try := func(f Box, width, height float64, turned bool) {
+		if width > f.Width()+epsilon || height > f.Height()+epsilon {
+			return
+		}
+		short := math.Min(f.Width()-width, f.Height()-height)
+		long := math.Max(f.Width()-width, f.Height()-height)
+		if short < bestShort || short == bestShort && long < bestLong {
+			spot, rotated, found = f.Min, turned, true
+			bestShort, bestLong = short, long
+		}
+	}
+	for _, f := range free {
+		try(f, item.Width, item.Height, false)
+		if rotate {
+			try(f, item.Height, item.Width, true)
+		}
+	}
+	return spot, rotated, found
+}
+
+// splitFree takes placed out of the free rectangles, replacing each one it
+// overlaps with the largest rectangles left on each side of it, and then
+// drops any free rectangle inside another.
+func splitFree(free []Box, placed Box) []Box {
+	var next []Box
+	for _, f := range free {
+		if !overlapsInside(f, placed) {
+			next = append(next, f)
+			continue
+		}
+		if placed.Min.X > f.Min.X+epsilon {
+			next = append(next, Box{f.Min, Point{placed.Min.X, f.Max.Y}})
+		}
+		if placed.Max.X < f.Max.X-epsilon {
+			next = append(next, Box{Point{placed.Max.X, f.Min.Y}, f.Max})
+		}
+		if placed.Min.Y > f.Min.Y+epsilon {
+			next = append(next, Box{f.Min, Point{f.Max.X, placed.Min.Y}})
+		}
+		if placed.Max.Y < f.Max.Y-epsilon {
+			next = append(next, Box{Point{f.Min.X, placed.Max.Y}, f.Max})
+		}
+	}
+
+	var pruned []Box
+	for i, f := range next {
+		contained := false
+		for j, g := range next {
+			if i != j && g.ContainsBox(f) && (f != g || j < i) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			pruned = append(pruned, f)
+		}
+	}
+	return pruned
+}
+
+// overlapsInside reports whether the boxes share more than an edge.
+func overlapsInside(a, b Box) bool {
+	return a.Min.X < b.Max.X-epsilon && b.Min.X < a.Max.X-epsilon &&
+		a.Min.Y < b.Max.Y-epsilon && b.Min.Y < a.Max.Y-epsilon
+}
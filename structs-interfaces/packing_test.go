@@ -0,0 +1,141 @@
+package structs_interfaces
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestPack(t *testing.T) {
+	t.Run("perfect fit", func(t *testing.T) {
+		items := []Rectangle{{2, 2}, {2, 2}, {4, 2}}
+
+		packing, err := Pack(Rectangle{4, 4}, items, PackOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertPacking(t, Rectangle{4, 4}, items, packing)
+		if len(packing.Unplaced) != 0 || !almostEqual(packing.Utilization, 1) {
+			t.Errorf("got unplaced %v utilization %g", packing.Unplaced, packing.Utilization)
+		}
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		items := []Rectangle{{5, 2}}
+
+		packing, _ := Pack(Rectangle{2, 5}, items, PackOptions{})
+		if !reflect.DeepEqual(packing.Unplaced, []int{0}) || packing.Utilization != 0 {
+			t.Errorf("got unplaced %v utilization %g without rotation", packing.Unplaced, packing.Utilization)
+		}
+
+		packing, _ = Pack(Rectangle{2, 5}, items, PackOptions{AllowRotation: true})
+		if len(packing.Packed) != 1 || !packing.Packed[0].Rotated {
+			t.Fatalf("got %+v want the item packed rotated", packing.Packed)
+		}
+		if got := packing.Packed[0].Shape.Shape; got != (Rectangle{2, 5}) {
+			t.Errorf("got %v want the rotated rectangle", got)
+		}
+		assertPacking(t, Rectangle{2, 5}, items, packing)
+	})
+
+	t.Run("unplaced items", func(t *testing.T) {
+		items := []Rectangle{{3, 3}, {1, 1}, {3, 3}, {5, 1}}
+
+		packing, err := Pack(Rectangle{4, 4}, items, PackOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(packing.Unplaced, []int{2, 3}) {
+			t.Errorf("got unplaced %v want [2 3]", packing.Unplaced)
+		}
+		if !almostEqual(packing.Utilization, 10.0/16) {
+			t.Errorf("got utilization %g want %g", packing.Utilization, 10.0/16)
+		}
+		assertPacking(t, Rectangle{4, 4}, items, packing)
+	})
+
+	t.Run("random labels", func(t *testing.T) {
+		random := rand.New(rand.NewSource(11))
+		for i := 0; i < 20; i++ {
+			container := Rectangle{20 + random.Float64()*20, 20 + random.Float64()*20}
+			items := make([]Rectangle, 10+random.Intn(60))
+			for j := range items {
+				items[j] = Rectangle{1 + random.Float64()*8, 1 + random.Float64()*4}
+			}
+
+			packing, err := Pack(container, items, PackOptions{AllowRotation: i%2 == 0})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertPacking(t, container, items, packing)
+		}
+	})
+
+	t.Run("invalid sizes", func(t *testing.T) {
+		if _, err := Pack(Rectangle{0, 1}, nil, PackOptions{}); !errors.Is(err, ErrInvalidDimension) {
+			t.Errorf("got %v want %v", err, ErrInvalidDimension)
+		}
+		if _, err := Pack(Rectangle{4, 4}, []Rectangle{{1, 1}, {-1, 1}}, PackOptions{}); !errors.Is(err, ErrInvalidDimension) {
+			t.Errorf("got %v want %v", err, ErrInvalidDimension)
+		}
+	})
+
+	t.Run("renders", func(t *testing.T) {
+		packing, _ := Pack(Rectangle{4, 4}, []Rectangle{{2, 2}, {1, 3}}, PackOptions{})
+		figures := []Figure{{Shape: Rectangle{4, 4}}}
+		for _, shape := range packing.Shapes() {
+			figures = append(figures, Figure{Shape: shape, Style: Style{Fill: "silver"}})
+		}
+
+		var out bytes.Buffer
+		if err := WriteSVG(&out, figures, SVGOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if got := bytes.Count(out.Bytes(), []byte("<polygon")); got != 3 {
+			t.Errorf("got %d polygons want 3", got)
+		}
+	})
+}
+
+// assertPacking checks every item is packed or unplaced exactly once, at its
+// own size, inside the container and clear of the other items.
+func assertPacking(t *testing.T, container Rectangle, items []Rectangle, packing Packing) {
+	t.Helper()
+	seen := map[int]bool{}
+	for _, i := range packing.Unplaced {
+		seen[i] = true
+	}
+	var used float64
+	bounds := Box{Point{0, 0}, Point{container.Width, container.Height}}
+	for n, item := range packing.Packed {
+		if seen[item.Index] {
+			t.Fatalf("item %d appears twice", item.Index)
+		}
+		seen[item.Index] = true
+		used += item.Shape.Area()
+
+		if !almostEqual(item.Shape.Area(), items[item.Index].Area()) {
+			t.Errorf("item %d: got area %g want %g", item.Index, item.Shape.Area(), items[item.Index].Area())
+		}
+		box, _ := BoundsOf(item.Shape)
+		if !(Box{Point{-epsilon, -epsilon}, Point{bounds.Max.X + epsilon, bounds.Max.Y + epsilon}}).ContainsBox(box) {
+			t.Errorf("item %d at %v is outside the container", item.Index, box)
+		}
+		for _, other := range packing.Packed[:n] {
+			otherBox, _ := BoundsOf(other.Shape)
+			if overlapsInside(box, otherBox) {
+				t.Errorf("items %d and %d overlap", item.Index, other.Index)
+			}
+		}
+	}
+	if len(seen) != len(items) {
+		t.Errorf("got %d items accounted for want %d", len(seen), len(items))
+	}
+	if !almostEqual(packing.Utilization, used/container.Area()) {
+		t.Errorf("got utilization %g want %g", packing.Utilization, used/container.Area())
+	}
+}
@@ -0,0 +1,261 @@
+package structs_interfaces
+
+import (
+	"fmt"
+	"math"
+)
+
+var (
+	ErrDegeneratePolygon = fmt.Errorf("%w: polygon is degenerate", ErrInvalidDimension)
+	ErrSelfIntersecting  = fmt.Errorf("%w: polygon intersects itself", ErrInvalidDimension)
+)
+
+// epsilon is the tolerance used when deciding whether points are collinear or
+// an area is zero.
+const epsilon = 1e-9
+
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type Orientation int
+
+const (
+	Collinear Orientation = iota
+	Clockwise
+	CounterClockwise
+)
+
+func (o Orientation) String() string {
+	switch o {
+	case Clockwise:
+		return "clockwise"
+	case CounterClockwise:
+		return "counter-clockwise"
+	default:
+		return "collinear"
+	}
+}
+
+// Polygon is a closed shape through Points, in order. The last point joins
+// back to the first, so it should not be repeated.
+type Polygon struct {
+	Points []Point `json:"points"`
+}
+
+// NewPolygon builds a polygon, rejecting fewer than three points or points
+// that enclose no area.
+func NewPolygon(points ...Point) (Polygon, error) {
+	p := Polygon{Points: append([]Point(nil), points...)}
+	return p, p.Validate()
+}
+
+// NewSimplePolygon builds a polygon like NewPolygon and also rejects edges
+// that cross or touch each other.
+func NewSimplePolygon(points ...Point) (Polygon, error) {
+	p, err := NewPolygon(points...)
+	if err != nil {
+		return p, err
+	}
+	if !p.IsSimple() {
+		return p, ErrSelfIntersecting
+	}
+	return p, nil
+}
+
+// Area uses the shoelace formula.
+func (p Polygon) Area() float64 {
+	return math.Abs(p.SignedArea())
+}
+
+// SignedArea is positive for counter-clockwise points and negative for
+// clockwise ones.
+func (p Polygon) SignedArea() float64 {
+	var sum float64
+	for i, a := range p.Points {
+		b := p.Points[(i+1)%len(p.Points)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+	return sum / 2
+}
+
+func (p Polygon) Perimeter() float64 {
+	var sum float64
+	for i, a := range p.Points {
+		sum += distance(a, p.Points[(i+1)%len(p.Points)])
+	}
+	return sum
+}
+
+// Centroid is the centre of mass of the enclosed area.
+func (p Polygon) Centroid() Point {
+	area := p.SignedArea()
+	if math.Abs(area) < epsilon {
+		return p.vertexMean()
+	}
+
+	var cx, cy float64
+	for i, a := range p.Points {
+		b := p.Points[(i+1)%len(p.Points)]
+		cross := a.X*b.Y - b.X*a.Y
+		cx += (a.X + b.X) * cross
+		cy += (a.Y + b.Y) * cross
+	}
+	return Point{cx / (6 * area), cy / (6 * area)}
+}
+
+func (p Polygon) Orientation() Orientation {
+	area := p.SignedArea()
+	switch {
+	case area > epsilon:
+		return CounterClockwise
+	case area < -epsilon:
+		return Clockwise
+	default:
+		return Collinear
+	}
+}
+
+// IsConvex reports whether every turn along the polygon goes the same way.
+// Collinear points are allowed.
+func (p Polygon) IsConvex() bool {
+	if len(p.Points) < 3 || !p.IsSimple() {
+		return false
+	}
+	var direction float64
+	for i := range p.Points {
+		turn := cross(p.Points[i], p.Points[(i+1)%len(p.Points)], p.Points[(i+2)%len(p.Points)])
+		if math.Abs(turn) < epsilon {
+			continue
+		}
+		if direction != 0 && (turn > 0) != (direction > 0) {
+			return false
+		}
+		direction = turn
+	}
+	return direction != 0
+}
+
+// IsSimple reports whether the polygon's edges only meet at the vertices they
+// share with their neighbours.
+func (p Polygon) IsSimple() bool {
+	n := len(p.Points)
+	if n < 3 {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		a1, a2 := p.Points[i], p.Points[(i+1)%n]
+		if a1 == a2 {
+			return false
+		}
+		for j := i + 1; j < n; j++ {
+			b1, b2 := p.Points[j], p.Points[(j+1)%n]
+			adjacent := j == i+1 || (i == 0 && j == n-1)
+			if adjacent {
+				// Neighbouring edges share a vertex, so they only intersect
+				// wrongly if the second folds back over the first.
+				from, shared, to := a1, a2, b2
+				if j != i+1 {
+					from, shared, to = b1, a1, a2
+				}
+				if math.Abs(cross(from, shared, to)) < epsilon && dot(sub(from, shared), sub(to, shared)) > 0 {
+					return false
+				}
+				continue
+			}
+			if segmentsIntersect(a1, a2, b1, b2) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (p Polygon) Validate() error {
+	if len(p.Points) < 3 || p.Orientation() == Collinear {
+		return ErrDegeneratePolygon
+	}
+	for _, point := range p.Points {
+		if math.IsNaN(point.X) || math.IsNaN(point.Y) || math.IsInf(point.X, 0) || math.IsInf(point.Y, 0) {
+			return fmt.Errorf("%w: point %v is not finite", ErrInvalidDimension, point)
+		}
+	}
+	return nil
+}
+
+// MultiPolygon is a group of separate polygons treated as one shape.
+type MultiPolygon struct {
+	Polygons []Polygon `json:"polygons"`
+}
+
+func (m MultiPolygon) Area() float64 {
+	var sum float64
+	for _, p := range m.Polygons {
+		sum += p.Area()
+	}
+	return sum
+}
+
+func (m MultiPolygon) Perimeter() float64 {
+	var sum float64
+	for _, p := range m.Polygons {
+		sum += p.Perimeter()
+	}
+	return sum
+}
+
+func (m MultiPolygon) Validate() error {
+	for i, p := range m.Polygons {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("polygon %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (p Polygon) vertexMean() Point {
+	var x, y float64
+	for _, point := range p.Points {
+		x += point.X
+		y += point.Y
+	}
+	n := float64(len(p.Points))
+	return Point{x / n, y / n}
+}
+
+// cross is the z component of (b - a) × (c - a): positive when a, b, c turn
+// counter-clockwise.
+func cross(a, b, c Point) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+func sub(a, b Point) Point {
+	return Point{a.X - b.X, a.Y - b.Y}
+}
+
+func dot(a, b Point) float64 {
+	return a.X*b.X + a.Y*b.Y
+}
+
+func distance(a, b Point) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
+
+// onSegment reports whether p lies on the segment from a to b.
+func onSegment(a, b, p Point) bool {
+	return math.Abs(cross(a, b, p)) < epsilon &&
+		math.Min(a.X, b.X)-epsilon <= p.X && p.X <= math.Max(a.X, b.X)+epsilon &&
+		math.Min(a.Y, b.Y)-epsilon <= p.Y && p.Y <= math.Max(a.Y, b.Y)+epsilon
+}
+
+// segmentsIntersect reports whether segment a1-a2 touches segment b1-b2.
+func segmentsIntersect(a1, a2, b1, b2 Point) bool {
+	d1, d2 := cross(b1, b2, a1), cross(b1, b2, a2)
+	d3, d4 := cross(a1, a2, b1), cross(a1, a2, b2)
+	if ((d1 > epsilon && d2 < -epsilon) || (d1 < -epsilon && d2 > epsilon)) &&
+		((d3 > epsilon && d4 < -epsilon) || (d3 < -epsilon && d4 > epsilon)) {
+		return true
+	}
+	return onSegment(b1, b2, a1) || onSegment(b1, b2, a2) || onSegment(a1, a2, b1) || onSegment(a1, a2, b2)
+}
@@ -0,0 +1,204 @@
+package structs_interfaces
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var (
+	square    = []Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	lShape    = []Point{{0, 0}, {4, 0}, {4, 1}, {1, 1}, {1, 3}, {0, 3}}
+	bowtie    = []Point{{0, 0}, {4, 4}, {4, 0}, {0, 2}}
+	collinear = []Point{{0, 0}, {1, 1}, {2, 2}}
+)
+
+func TestPolygon(t *testing.T) {
+	t.Run("area and perimeter", func(t *testing.T) {
+		polygonTests := []struct {
+			points    []Point
+			area      float64
+			perimeter float64
+		}{
+			{square, 16, 16},
+			{lShape, 6, 14},
+			{[]Point{{0, 0}, {3, 0}, {0, 4}}, 6, 12},
+		}
+
+		for _, tt := range polygonTests {
+			polygon := Polygon{tt.points}
+			if got := polygon.Area(); !almostEqual(got, tt.area) {
+				t.Errorf("%v area got %g want %g", tt.points, got, tt.area)
+			}
+			if got := polygon.Perimeter(); !almostEqual(got, tt.perimeter) {
+				t.Errorf("%v perimeter got %g want %g", tt.points, got, tt.perimeter)
+			}
+		}
+	})
+
+	t.Run("centroid", func(t *testing.T) {
+		got := Polygon{lShape}.Centroid()
+		want := Point{1.5, 1}
+		if !almostEqual(got.X, want.X) || !almostEqual(got.Y, want.Y) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("orientation", func(t *testing.T) {
+		reversed := []Point{{0, 4}, {4, 4}, {4, 0}, {0, 0}}
+
+		assertOrientation(t, Polygon{square}.Orientation(), CounterClockwise)
+		assertOrientation(t, Polygon{reversed}.Orientation(), Clockwise)
+		assertOrientation(t, Polygon{collinear}.Orientation(), Collinear)
+	})
+
+	t.Run("convexity", func(t *testing.T) {
+		withCollinearPoint := []Point{{0, 0}, {2, 0}, {4, 0}, {4, 4}, {0, 4}}
+
+		if !(Polygon{square}).IsConvex() || !(Polygon{withCollinearPoint}).IsConvex() {
+			t.Error("expected convex polygons")
+		}
+		if (Polygon{lShape}).IsConvex() || (Polygon{bowtie}).IsConvex() {
+			t.Error("expected non-convex polygons")
+		}
+	})
+
+	t.Run("self intersection", func(t *testing.T) {
+		spike := []Point{{0, 0}, {4, 0}, {2, 0}, {2, 3}}
+		touching := []Point{{0, 0}, {4, 0}, {4, 4}, {2, 0}, {0, 4}}
+
+		if !(Polygon{square}).IsSimple() || !(Polygon{lShape}).IsSimple() {
+			t.Error("expected simple polygons")
+		}
+		for _, points := range [][]Point{bowtie, spike, touching} {
+			if (Polygon{points}).IsSimple() {
+				t.Errorf("expected %v to intersect itself", points)
+			}
+		}
+	})
+}
+
+func TestNewPolygon(t *testing.T) {
+	if _, err := NewPolygon(square...); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	_, err := NewPolygon(collinear...)
+	assertPolygonError(t, err, ErrDegeneratePolygon)
+	_, err = NewPolygon(Point{0, 0}, Point{1, 1})
+	assertPolygonError(t, err, ErrDegeneratePolygon)
+
+	if _, err := NewPolygon(bowtie...); err != nil {
+		t.Errorf("NewPolygon should accept self-intersecting input, got %v", err)
+	}
+	_, err = NewSimplePolygon(bowtie...)
+	assertPolygonError(t, err, ErrSelfIntersecting)
+
+	if !errors.Is(err, ErrInvalidDimension) {
+		t.Error("expected polygon errors to be invalid dimensions")
+	}
+}
+
+func assertOrientation(t *testing.T, got, want Orientation) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %s want %s", got, want)
+	}
+}
+
+func assertPolygonError(t *testing.T, got, want error) {
+	t.Helper()
+	if !errors.Is(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestMultiPolygonOutlines(t *testing.T) {
+	// Two islands with a strait between them, 4 < x < 6.
+	islands := MultiPolygon{[]Polygon{{square}, {[]Point{{6, 0}, {10, 0}, {10, 4}, {6, 4}}}}}
+	boat := Place(Circle{0.5}, Translate(5, 2))
+
+	t.Run("outlines", func(t *testing.T) {
+		outlines, err := OutlinesOf(Place(islands, Translate(0, 1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(outlines) != 2 {
+			t.Fatalf("got %d outlines want one for each island", len(outlines))
+		}
+		assertPoint(t, outlines[1].Points[0], Point{6, 1})
+	})
+
+	t.Run("bounds", func(t *testing.T) {
+		got, err := BoundsOf(islands)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != (Box{Point{0, 0}, Point{10, 4}}) {
+			t.Errorf("got %v", got)
+		}
+		if _, err := BoundsOf(MultiPolygon{}); !errors.Is(err, ErrInvalidDimension) {
+			t.Errorf("got %v want %v for no polygons", err, ErrInvalidDimension)
+		}
+	})
+
+	t.Run("collisions", func(t *testing.T) {
+		if hit, err := Intersects(islands, boat); err != nil || hit {
+			t.Errorf("got %t, %v for a shape between the polygons", hit, err)
+		}
+		if hit, _ := Intersects(islands, Place(Circle{0.5}, Translate(8, 2))); !hit {
+			t.Error("expected a shape on the second polygon to collide")
+		}
+
+		got, err := Collisions([]Shape{islands, boat, Place(Square{1}, Translate(9, 3))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []Pair{{0, 2}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("spatial index", func(t *testing.T) {
+		index := NewSpatialIndex()
+		id, err := index.Insert(islands)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := index.At(Point{8, 2}); !reflect.DeepEqual(got, []ShapeID{id}) {
+			t.Errorf("got %v on the second polygon", got)
+		}
+		if got := index.Search(Box{Point{4.5, 1}, Point{5.5, 3}}); len(got) != 0 {
+			t.Errorf("got %v searching between the polygons", got)
+		}
+		if _, err := index.Insert(MultiPolygon{}); !errors.Is(err, ErrInvalidDimension) {
+			t.Errorf("got %v want %v for no polygons", err, ErrInvalidDimension)
+		}
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := WriteSVG(&out, []Figure{{Shape: islands}}, SVGOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		want := `<path d="M0,0 L4,0 L4,-4 L0,-4 Z M6,0 L10,0 L10,-4 L6,-4 Z" fill-rule="nonzero"`
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("got\n%s\nwant it to contain\n%s", out.String(), want)
+		}
+	})
+
+	t.Run("raster", func(t *testing.T) {
+		figures := []Figure{{Shape: islands, Style: Style{Fill: "blue", Stroke: "none"}}}
+		img, err := Rasterize(figures, RasterOptions{Margin: -0.5, Background: "none"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for x, want := range []uint8{255, 255, 255, 255, 0, 0, 255, 255, 255, 255} {
+			if got := img.RGBAAt(x, 2); got.A != want {
+				t.Errorf("pixel %d,2: got alpha %d want %d", x, got.A, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,317 @@
+package structs_interfaces
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// rasterSubsamples is how many rows of samples each row of pixels is split
+// into. Across a row coverage is worked out exactly.
+const rasterSubsamples = 16
+
+// rasterTolerance is how far, in pixels, curves may stray when they are drawn
+// as polygons.
+const rasterTolerance = 0.05
+
+// maxRasterPixels caps the size of images, which take four bytes a pixel, at
+// 128 MiB.
+const maxRasterPixels = 1 << 25
+
+type RasterOptions struct {
+	// Width and Height are the size of the image in pixels. If only one is
+	// set the other follows the shapes' aspect ratio, and if neither is, the
+	// image is Scale pixels per unit, 1 by default.
+	Width  int
+	Height int
+	Scale  float64
+	// Margin is added around the shapes, in shape units.
+	Margin float64
+	// Background is the colour behind the shapes, white by default. Use
+	// "none" for a transparent image.
+	Background string
+}
+
+// Rasterize draws figures the way WriteSVG does, into an anti-aliased image
+// fitted around them. Colours can be "#rgb", "#rrggbb", "none" or one of the
+// basic CSS colour names. Labels are not drawn, and images of more than
+// maxRasterPixels pixels are refused.
+func Rasterize(figures []Figure, options RasterOptions) (*image.RGBA, error) {
+	outlines, box, err := figureOutlines(figures)
+	if err != nil {
+		return nil, err
+	}
+	background, err := parseColor(orDefault(options.Background, "white"))
+	if err != nil {
+		return nil, fmt.Errorf("background: %w", err)
+	}
+	fills, strokes := make([]color.NRGBA, len(figures)), make([]color.NRGBA, len(figures))
+	for i, f := range figures {
+		if fills[i], err = parseColor(orDefault(f.Style.Fill, "none")); err != nil {
+			return nil, fmt.Errorf("figure %d fill: %w", i, err)
+		}
+		if strokes[i], err = parseColor(orDefault(f.Style.Stroke, "black")); err != nil {
+			return nil, fmt.Errorf("figure %d stroke: %w", i, err)
+		}
+	}
+
+	width, height := box.Width()+2*options.Margin, box.Height()+2*options.Margin
+	if !(width > 0) || !(height > 0) {
+		return nil, fmt.Errorf("%w: there is nothing to draw", ErrInvalidDimension)
+	}
+	pixelsWide, pixelsHigh := options.Width, options.Height
+	var scale float64
+	switch {
+	case pixelsWide > 0 && pixelsHigh > 0:
+		scale = math.Min(float64(pixelsWide)/width, float64(pixelsHigh)/height)
+	case pixelsWide > 0:
+		scale = float64(pixelsWide) / width
+		pixelsHigh = int(math.Ceil(height * scale))
+	case pixelsHigh > 0:
+		scale = float64(pixelsHigh) / height
+		pixelsWide = int(math.Ceil(width * scale))
+	default:
+		scale = options.Scale
+		if scale <= 0 {
+			scale = 1
+		}
+		pixelsWide, pixelsHigh = int(math.Ceil(width*scale)), int(math.Ceil(height*scale))
+	}
+	if pixelsWide < 1 || pixelsHigh < 1 || float64(pixelsWide)*float64(pixelsHigh) > maxRasterPixels {
+		return nil, fmt.Errorf("%w: a %d by %d image is too big to draw", ErrInvalidDimension, pixelsWide, pixelsHigh)
+	}
+
+	// Centre the shapes in the image, flipping y to point down.
+	device := Translate(-(box.Min.X - options.Margin), -(box.Max.Y + options.Margin)).
+		Then(Scale(scale, -scale)).
+		Then(Translate((float64(pixelsWide)-width*scale)/2, (float64(pixelsHigh)-height*scale)/2))
+
+	img := image.NewRGBA(image.Rect(0, 0, pixelsWide, pixelsHigh))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = premultiply(background)
+	}
+
+	for i, f := range figures {
+		var paths, stroke [][]Point
+		for _, outline := range outlines[i] {
+			points := rasterPoints(outline.Transform(device))
+			paths = append(paths, points)
+			stroke = append(stroke, strokePaths(points, strokeWidth(f.Style)*scale)...)
+		}
+		fillPaths(img, paths, fills[i])
+		fillPaths(img, stroke, strokes[i])
+	}
+	return img, nil
+}
+
+// WritePNG rasterizes figures and encodes the image as PNG.
+func WritePNG(w io.Writer, figures []Figure, options RasterOptions) error {
+	img, err := Rasterize(figures, options)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// rasterPoints is the outline as a polygon, standing in for curves with one
+// close enough not to show. The way it goes round is kept, so the holes of
+// shapes with several parts stay empty.
+func rasterPoints(o Outline) []Point {
+	if o.Curved {
+		return curvePolygon(o.Curve, rasterTolerance)
+	}
+	return o.Points
+}
+
+// strokePaths covers the line around points, width wide, with a rectangle
+// along each edge and a disc at each corner. They all turn the same way, so
+// where they overlap they fill once under the non-zero rule.
+func strokePaths(points []Point, width float64) [][]Point {
+	half := width / 2
+	var paths [][]Point
+	for i, a := range points {
+		b := points[(i+1)%len(points)]
+		length := distance(a, b)
+		if length == 0 {
+			continue
+		}
+		n := Point{-(b.Y - a.Y) / length * half, (b.X - a.X) / length * half}
+		paths = append(paths,
+			positive([]Point{{a.X + n.X, a.Y + n.Y}, {b.X + n.X, b.Y + n.Y}, {b.X - n.X, b.Y - n.Y}, {a.X - n.X, a.Y - n.Y}}),
+			positive(curvePolygon(Scale(half, half).Then(Translate(a.X, a.Y)), rasterTolerance)))
+	}
+	return paths
+}
+
+func positive(points []Point) []Point {
+	if (Polygon{points}).SignedArea() < 0 {
+		points = reversed(points)
+	}
+	return points
+}
+
+type rasterEdge struct {
+	from, to Point
+	winding  int
+}
+
+type rasterCrossing struct {
+	x       float64
+	winding int
+}
+
+// fillPaths paints c over the area inside paths, by the non-zero winding
+// rule, blending edge pixels by how much of them is covered.
+func fillPaths(img *image.RGBA, paths [][]Point, c color.NRGBA) {
+	if c.A == 0 || len(paths) == 0 {
+		return
+	}
+	var edges []rasterEdge
+	var box Box
+	for i, path := range paths {
+		bounds := Outline{Points: path}.Bounds()
+		if i == 0 {
+			box = bounds
+		} else {
+			box = box.Union(bounds)
+		}
+		for j, a := range path {
+			b := path[(j+1)%len(path)]
+			switch {
+			case a.Y < b.Y:
+				edges = append(edges, rasterEdge{a, b, 1})
+			case a.Y > b.Y:
+				edges = append(edges, rasterEdge{b, a, -1})
+			}
+		}
+	}
+
+	size := img.Bounds().Size()
+	firstRow, lastRow := int(math.Max(0, math.Floor(box.Min.Y))), int(math.Min(float64(size.Y), math.Ceil(box.Max.Y)))
+	firstCol, lastCol := int(math.Max(0, math.Floor(box.Min.X))), int(math.Min(float64(size.X), math.Ceil(box.Max.X)))
+	if firstRow >= lastRow || firstCol >= lastCol {
+		return
+	}
+
+	cover := make([]float64, lastCol-firstCol)
+	var crossings []rasterCrossing
+	for row := firstRow; row < lastRow; row++ {
+		for i := range cover {
+			cover[i] = 0
+		}
+		for s := 0; s < rasterSubsamples; s++ {
+			y := float64(row) + (float64(s)+0.5)/rasterSubsamples
+			crossings = crossings[:0]
+			for _, e := range edges {
+				if e.from.Y <= y && y < e.to.Y {
+					x := e.from.X + (y-e.from.Y)*(e.to.X-e.from.X)/(e.to.Y-e.from.Y)
+					crossings = append(crossings, rasterCrossing{x - float64(firstCol), e.winding})
+				}
+			}
+			sort.Slice(crossings, func(i, j int) bool { return crossings[i].x < crossings[j].x })
+
+			winding, start := 0, 0.0
+			for _, crossing := range crossings {
+				before := winding
+				winding += crossing.winding
+				if before == 0 && winding != 0 {
+					start = crossing.x
+				} else if before != 0 && winding == 0 {
+					addSpan(cover, start, crossing.x, 1.0/rasterSubsamples)
+				}
+			}
+		}
+
+		for i, coverage := range cover {
+			if coverage > 0 {
+				blend(img, firstCol+i, row, c, math.Min(coverage, 1))
+			}
+		}
+	}
+}
+
+// addSpan adds weight times how much of each pixel lies between x0 and x1.
+func addSpan(cover []float64, x0, x1, weight float64) {
+	x0, x1 = math.Max(x0, 0), math.Min(x1, float64(len(cover)))
+	if x1 <= x0 {
+		return
+	}
+	first, last := int(x0), int(x1)
+	if first == last {
+		cover[first] += (x1 - x0) * weight
+		return
+	}
+	cover[first] += (float64(first+1) - x0) * weight
+	for i := first + 1; i < last; i++ {
+		cover[i] += weight
+	}
+	if last < len(cover) {
+		cover[last] += (x1 - float64(last)) * weight
+	}
+}
+
+// blend paints c over the pixel at x, y with the given coverage. The image
+// holds premultiplied colours.
+func blend(img *image.RGBA, x, y int, c color.NRGBA, coverage float64) {
+	alpha := float64(c.A) / 255 * coverage
+	i := img.PixOffset(x, y)
+	for k, v := range [4]uint8{c.R, c.G, c.B, 255} {
+		img.Pix[i+k] = uint8(math.Round(float64(v)*alpha + float64(img.Pix[i+k])*(1-alpha)))
+	}
+}
+
+func premultiply(c color.NRGBA) (uint8, uint8, uint8, uint8) {
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	return rgba.R, rgba.G, rgba.B, rgba.A
+}
+
+var colorNames = map[string]color.NRGBA{
+	"none":    {},
+	"black":   {0, 0, 0, 255},
+	"white":   {255, 255, 255, 255},
+	"gray":    {128, 128, 128, 255},
+	"grey":    {128, 128, 128, 255},
+	"silver":  {192, 192, 192, 255},
+	"red":     {255, 0, 0, 255},
+	"maroon":  {128, 0, 0, 255},
+	"orange":  {255, 165, 0, 255},
+	"yellow":  {255, 255, 0, 255},
+	"olive":   {128, 128, 0, 255},
+	"lime":    {0, 255, 0, 255},
+	"green":   {0, 128, 0, 255},
+	"aqua":    {0, 255, 255, 255},
+	"cyan":    {0, 255, 255, 255},
+	"teal":    {0, 128, 128, 255},
+	"blue":    {0, 0, 255, 255},
+	"navy":    {0, 0, 128, 255},
+	"fuchsia": {255, 0, 255, 255},
+	"magenta": {255, 0, 255, 255},
+	"purple":  {128, 0, 128, 255},
+	"pink":    {255, 192, 203, 255},
+	"brown":   {165, 42, 42, 255},
+
+	"lightblue":  {173, 216, 230, 255},
+	"lightgreen": {144, 238, 144, 255},
+	"lightgray":  {211, 211, 211, 255},
+	"lightgrey":  {211, 211, 211, 255},
+}
+
+func parseColor(s string) (color.NRGBA, error) {
+	if c, ok := colorNames[s]; ok {
+		return c, nil
+	}
+	if len(s) == 4 && s[0] == '#' {
+		s = "#" + string([]byte{s[1], s[1], s[2], s[2], s[3], s[3]})
+	}
+	if len(s) == 7 && s[0] == '#' {
+		if v, err := strconv.ParseUint(s[1:], 16, 32); err == nil {
+			return color.NRGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}, nil
+		}
+	}
+	return color.NRGBA{}, fmt.Errorf("unknown colour %q", s)
+}
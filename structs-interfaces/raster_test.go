@@ -0,0 +1,178 @@
+package structs_interfaces
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRasterize(t *testing.T) {
+	rasterTests := []struct {
+		name    string
+		figures []Figure
+		options RasterOptions
+	}{
+		{
+			name: "basic_shapes",
+			figures: []Figure{
+				{Shape: Rectangle{4, 2}, Style: Style{Fill: "lightblue", Label: "room"}},
+				{Shape: Place(Circle{1}, Translate(6, 1)), Style: Style{Stroke: "red", StrokeWidth: 0.2}},
+				{Shape: Place(Triangle{2, 2}, Translate(0, 3))},
+			},
+			options: RasterOptions{Scale: 20, Margin: 1},
+		},
+		{
+			name: "transformed_shapes",
+			figures: []Figure{
+				{Shape: Place(Ellipse{3, 1}, Rotate(math.Pi/6)), Style: Style{Fill: "#ccc"}},
+				{Shape: Place(Square{2}, Rotate(math.Pi/4).Then(Translate(5, 0)))},
+				{Shape: Place(Polygon{lShape}, Scale(-1, 1)), Style: Style{Stroke: "green"}},
+			},
+			options: RasterOptions{Width: 200, Height: 150, Background: "none"},
+		},
+	}
+
+	for _, tt := range rasterTests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := Rasterize(tt.figures, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertGoldenImage(t, filepath.Join("testdata", tt.name+".png"), img)
+		})
+	}
+
+	t.Run("pixel aligned square", func(t *testing.T) {
+		figures := []Figure{{Shape: Square{4}, Style: Style{Fill: "blue", Stroke: "none"}}}
+
+		img, err := Rasterize(figures, RasterOptions{Scale: 2, Margin: 0.5, Background: "none"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := img.Bounds().Size(); got != (image.Point{12, 12}) {
+			t.Fatalf("got size %v want 12x12", got)
+		}
+		for y := 0; y < 12; y++ {
+			for x := 0; x < 12; x++ {
+				var want uint8
+				if x >= 2 && x < 10 && y >= 2 && y < 10 {
+					want = 255
+				}
+				if got := img.RGBAAt(x, y); got.A != want || got.B != want || got.R != 0 {
+					t.Fatalf("pixel %d,%d: got %v want blue with alpha %d", x, y, got, want)
+				}
+			}
+		}
+	})
+
+	t.Run("circle coverage", func(t *testing.T) {
+		figures := []Figure{{Shape: Place(Circle{10.3}, Translate(0.4, 0.7)), Style: Style{Fill: "black", Stroke: "none"}}}
+
+		img, err := Rasterize(figures, RasterOptions{Scale: 3, Margin: 1, Background: "none"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var covered float64
+		for i := 3; i < len(img.Pix); i += 4 {
+			covered += float64(img.Pix[i]) / 255
+		}
+		want := math.Pi * 30.9 * 30.9
+		if math.Abs(covered-want) > want*0.002 {
+			t.Errorf("got %g pixels covered want %g", covered, want)
+		}
+	})
+
+	t.Run("bad colours", func(t *testing.T) {
+		figures := []Figure{{Shape: Square{1}}, {Shape: Square{1}, Style: Style{Fill: "#12345"}}}
+		if _, err := Rasterize(figures, RasterOptions{}); err == nil {
+			t.Error("expected an error for an unknown colour")
+		}
+		if _, err := Rasterize(figures[:1], RasterOptions{Background: "plaid"}); err == nil {
+			t.Error("expected an error for an unknown background")
+		}
+	})
+
+	t.Run("too big", func(t *testing.T) {
+		sizes := []RasterOptions{{}, {Width: 100000, Height: 100000}, {Scale: 1e300}}
+		for _, options := range sizes {
+			_, err := Rasterize([]Figure{{Shape: Rectangle{1e5, 1e5}}}, options)
+			if !errors.Is(err, ErrInvalidDimension) {
+				t.Errorf("%+v: got %v want %v", options, err, ErrInvalidDimension)
+			}
+		}
+	})
+
+	t.Run("unknown shapes", func(t *testing.T) {
+		_, err := Rasterize([]Figure{{Shape: blob{}}}, RasterOptions{})
+		if !errors.Is(err, ErrUnknownShape) {
+			t.Errorf("got %v want %v", err, ErrUnknownShape)
+		}
+	})
+}
+
+func TestWritePNG(t *testing.T) {
+	var out bytes.Buffer
+	if err := WritePNG(&out, []Figure{{Shape: Rectangle{4, 2}}}, RasterOptions{Width: 80}); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds().Size(); got != (image.Point{80, 48}) {
+		t.Errorf("got size %v want 80x48", got)
+	}
+}
+
+// assertGoldenImage allows channels to be a little off, and a few pixels to
+// be further off, so small changes in rounding do not break it.
+func assertGoldenImage(t *testing.T, path string, got *image.RGBA) {
+	t.Helper()
+	if *update {
+		var out bytes.Buffer
+		if err := png.Encode(&out, got); err != nil {
+			t.Fatal(err)
+		}
+		assertGolden(t, path, out.Bytes())
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal("could not read golden file, run with -update to create it:", err)
+	}
+	defer f.Close()
+	decoded, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := image.NewRGBA(decoded.Bounds())
+	for y := want.Rect.Min.Y; y < want.Rect.Max.Y; y++ {
+		for x := want.Rect.Min.X; x < want.Rect.Max.X; x++ {
+			want.Set(x, y, decoded.At(x, y))
+		}
+	}
+	if got.Bounds() != want.Bounds() {
+		t.Fatalf("got size %v want %v from %s", got.Bounds(), want.Bounds(), path)
+	}
+
+	const tolerance, allowed = 8, 0.002
+	var wrong int
+	for i := 0; i < len(got.Pix); i += 4 {
+		for k := 0; k < 4; k++ {
+			if math.Abs(float64(got.Pix[i+k])-float64(want.Pix[i+k])) > tolerance {
+				wrong++
+				break
+			}
+		}
+	}
+	if pixels := len(got.Pix) / 4; float64(wrong) > allowed*float64(pixels) {
+		t.Errorf("%d of %d pixels differ from %s", wrong, pixels, path)
+	}
+}
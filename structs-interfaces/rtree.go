@@ -0,0 +1,379 @@
+package structs_interfaces
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var ErrShapeNotFound = errors.New("no shape with that id")
+
+const (
+	maxNodeEntries = 16
+	minNodeEntries = 6
+)
+
+// ShapeID identifies a shape in a SpatialIndex.
+type ShapeID int
+
+// Neighbour is a shape found near a point. Distance is 0 for shapes holding
+// the point.
+type Neighbour struct {
+	ID       ShapeID
+	Distance float64
+}
+
+// SpatialIndex is an R-tree over the bounding boxes of shapes. Queries use
+// the boxes to find candidates and then test the shapes' outlines exactly.
+type SpatialIndex struct {
+	root   *rtreeNode
+	shapes map[ShapeID]*rtreeNode
+	nextID ShapeID
+}
+
+// rtreeNode is either a shape, with no children, or a node of the tree. The
+// children of a leaf node are shapes.
+type rtreeNode struct {
+	box      Box
+	parent   *rtreeNode
+	children []*rtreeNode
+	leaf     bool
+
+	id     ShapeID
+	shape  Shape
+	region region
+}
+
+func NewSpatialIndex() *SpatialIndex {
+	return &SpatialIndex{root: &rtreeNode{leaf: true}, shapes: map[ShapeID]*rtreeNode{}}
+}
+
+func (t *SpatialIndex) Len() int {
+	return len(t.shapes)
+}
+
+// Insert adds shape to the index and returns the id to find or delete it by.
+// Shapes that are not valid or cover nothing are not added.
+func (t *SpatialIndex) Insert(shape Shape) (ShapeID, error) {
+	if err := Validate(shape); err != nil {
+		return 0, err
+	}
+	r, err := regionOf(shape)
+	if err != nil {
+		return 0, err
+	}
+	if len(r.parts) == 0 {
+		return 0, fmt.Errorf("%w: %T covers nothing", ErrInvalidDimension, shape)
+	}
+	t.nextID++
+	entry := &rtreeNode{box: r.box, id: t.nextID, shape: shape, region: r}
+	t.shapes[entry.id] = entry
+	t.insert(entry)
+	return entry.id, nil
+}
+
+func (t *SpatialIndex) Shape(id ShapeID) (Shape, bool) {
+	entry, ok := t.shapes[id]
+	if !ok {
+		return nil, false
+	}
+	return entry.shape, true
+}
+
+func (t *SpatialIndex) Delete(id ShapeID) error {
+	entry, ok := t.shapes[id]
+	if !ok {
+		return ErrShapeNotFound
+	}
+	delete(t.shapes, id)
+	leaf := entry.parent
+	leaf.remove(entry)
+	t.condense(leaf)
+	return nil
+}
+
+// Search returns the shapes that overlap or touch area, in id order.
+func (t *SpatialIndex) Search(area Box) []ShapeID {
+	query, _ := regionOf(area.Rectangle())
+	var ids []ShapeID
+	t.root.visit(area.Intersects, func(entry *rtreeNode) {
+		if regionsIntersect(entry.region, query) {
+			ids = append(ids, entry.id)
+		}
+	})
+	sortIDs(ids)
+	return ids
+}
+
+// At returns the shapes holding p, including on their boundary, in id order.
+func (t *SpatialIndex) At(p Point) []ShapeID {
+	var ids []ShapeID
+	t.root.visit(func(b Box) bool { return b.ContainsPoint(p) }, func(entry *rtreeNode) {
+		if entry.region.contains(p, epsilon) {
+			ids = append(ids, entry.id)
+		}
+	})
+	sortIDs(ids)
+	return ids
+}
+
+// Nearest returns up to k shapes closest to p, nearest first. Nodes are
+// searched best first by the distance to their boxes, which is never more
+// than the distance to anything inside them.
+func (t *SpatialIndex) Nearest(p Point, k int) []Neighbour {
+	if k <= 0 || t.Len() == 0 {
+		return nil
+	}
+	queue := &nearestQueue{{node: t.root, distance: boxDistance(t.root.box, p)}}
+	var found []Neighbour
+	for queue.Len() > 0 && len(found) < k {
+		item := heap.Pop(queue).(nearestItem)
+		node := item.node
+		switch {
+		case item.exact:
+			found = append(found, Neighbour{node.id, item.distance})
+		case node.shape != nil:
+			heap.Push(queue, nearestItem{node: node, distance: math.Max(0, node.region.signedDistance(p)), exact: true})
+		default:
+			for _, child := range node.children {
+				heap.Push(queue, nearestItem{node: child, distance: boxDistance(child.box, p)})
+			}
+		}
+	}
+	return found
+}
+
+func (t *SpatialIndex) insert(entry *rtreeNode) {
+	node := t.root
+	for !node.leaf {
+		node = node.chooseChild(entry.box)
+	}
+	node.add(entry)
+
+	for ; node != nil; node = node.parent {
+		var split *rtreeNode
+		if len(node.children) > maxNodeEntries {
+			split = node.split()
+		}
+		node.refit()
+		if split == nil {
+			continue
+		}
+		if node.parent == nil {
+			t.root = &rtreeNode{}
+			t.root.add(node)
+			t.root.add(split)
+			t.root.refit()
+			return
+		}
+		node.parent.add(split)
+	}
+}
+
+// condense walks up from a node that lost a child, removing nodes left with
+// too few children and reinserting their shapes.
+func (t *SpatialIndex) condense(node *rtreeNode) {
+	var orphans []*rtreeNode
+	for node != t.root {
+		parent := node.parent
+		if len(node.children) < minNodeEntries {
+			parent.remove(node)
+			orphans = node.entries(orphans)
+		} else {
+			node.refit()
+		}
+		node = parent
+	}
+
+	for !t.root.leaf && len(t.root.children) == 1 {
+		t.root = t.root.children[0]
+		t.root.parent = nil
+	}
+	if len(t.root.children) == 0 {
+		t.root = &rtreeNode{leaf: true}
+	}
+	t.root.refit()
+
+	for _, entry := range orphans {
+		t.insert(entry)
+	}
+}
+
+func (n *rtreeNode) add(child *rtreeNode) {
+	n.children = append(n.children, child)
+	child.parent = n
+}
+
+func (n *rtreeNode) remove(child *rtreeNode) {
+	for i, c := range n.children {
+		if c == child {
+			n.children = append(n.children[:i], n.children[i+1:]...)
+			break
+		}
+	}
+	child.parent = nil
+}
+
+func (n *rtreeNode) refit() {
+	if len(n.children) == 0 {
+		n.box = Box{}
+		return
+	}
+	n.box = n.children[0].box
+	for _, child := range n.children[1:] {
+		n.box = n.box.Union(child.box)
+	}
+}
+
+// entries appends every shape under n to found.
+func (n *rtreeNode) entries(found []*rtreeNode) []*rtreeNode {
+	if n.leaf {
+		return append(found, n.children...)
+	}
+	for _, child := range n.children {
+		found = child.entries(found)
+	}
+	return found
+}
+
+func (n *rtreeNode) visit(overlaps func(Box) bool, match func(entry *rtreeNode)) {
+	for _, child := range n.children {
+		if !overlaps(child.box) {
+			continue
+		}
+		if n.leaf {
+			match(child)
+		} else {
+			child.visit(overlaps, match)
+		}
+	}
+}
+
+// chooseChild picks the child whose box grows least to take box, breaking
+// ties by the smaller box.
+func (n *rtreeNode) chooseChild(box Box) *rtreeNode {
+	var best *rtreeNode
+	bestGrowth, bestArea := math.Inf(1), math.Inf(1)
+	for _, child := range n.children {
+		area := child.box.Area()
+		growth := child.box.Union(box).Area() - area
+		if growth < bestGrowth || growth == bestGrowth && area < bestArea {
+			best, bestGrowth, bestArea = child, growth, area
+		}
+	}
+	return best
+}
+
+// split moves some of n's children into a new sibling using Guttman's
+// quadratic split, and returns the sibling.
+func (n *rtreeNode) split() *rtreeNode {
+	children := n.children
+	seedA, seedB, worst := 0, 1, math.Inf(-1)
+	for i := range children {
+		for j := i + 1; j < len(children); j++ {
+			waste := children[i].box.Union(children[j].box).Area() - children[i].box.Area() - children[j].box.Area()
+			if waste > worst {
+				seedA, seedB, worst = i, j, waste
+			}
+		}
+	}
+
+	a, b := []*rtreeNode{children[seedA]}, []*rtreeNode{children[seedB]}
+	boxA, boxB := children[seedA].box, children[seedB].box
+	var rest []*rtreeNode
+	for i, child := range children {
+		if i != seedA && i != seedB {
+			rest = append(rest, child)
+		}
+	}
+
+	for len(rest) > 0 {
+		if len(a)+len(rest) == minNodeEntries {
+			a, rest = append(a, rest...), nil
+			break
+		}
+		if len(b)+len(rest) == minNodeEntries {
+			b, rest = append(b, rest...), nil
+			break
+		}
+
+		// Place the child with the strongest preference for one group first.
+		pick, preference := 0, math.Inf(-1)
+		for i, child := range rest {
+			growA := boxA.Union(child.box).Area() - boxA.Area()
+			growB := boxB.Union(child.box).Area() - boxB.Area()
+			if d := math.Abs(growA - growB); d > preference {
+				pick, preference = i, d
+			}
+		}
+		child := rest[pick]
+		rest = append(rest[:pick], rest[pick+1:]...)
+
+		growA := boxA.Union(child.box).Area() - boxA.Area()
+		growB := boxB.Union(child.box).Area() - boxB.Area()
+		toA := growA < growB ||
+			growA == growB && (boxA.Area() < boxB.Area() || boxA.Area() == boxB.Area() && len(a) <= len(b))
+		if toA {
+			a, boxA = append(a, child), boxA.Union(child.box)
+		} else {
+			b, boxB = append(b, child), boxB.Union(child.box)
+		}
+	}
+
+	n.children = a
+	for _, child := range a {
+		child.parent = n
+	}
+	sibling := &rtreeNode{leaf: n.leaf}
+	for _, child := range b {
+		sibling.add(child)
+	}
+	n.refit()
+	sibling.refit()
+	return sibling
+}
+
+type nearestItem struct {
+	node     *rtreeNode
+	distance float64
+	exact    bool
+}
+
+type nearestQueue []nearestItem
+
+func (q nearestQueue) Len() int { return len(q) }
+
+// Less puts exact distances ahead of box distances that are equal to them, so
+// a shape is reported as soon as nothing can be closer.
+func (q nearestQueue) Less(i, j int) bool {
+	if q[i].distance != q[j].distance {
+		return q[i].distance < q[j].distance
+	}
+	if q[i].exact != q[j].exact {
+		return q[i].exact
+	}
+	return q[i].node.id < q[j].node.id
+}
+
+func (q nearestQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *nearestQueue) Push(x interface{}) { *q = append(*q, x.(nearestItem)) }
+
+func (q *nearestQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+func boxDistance(b Box, p Point) float64 {
+	dx := math.Max(0, math.Max(b.Min.X-p.X, p.X-b.Max.X))
+	dy := math.Max(0, math.Max(b.Min.Y-p.Y, p.Y-b.Max.Y))
+	return math.Hypot(dx, dy)
+}
+
+func sortIDs(ids []ShapeID) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
@@ -0,0 +1,284 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSpatialIndex(t *testing.T) {
+	t.Run("matches brute force", func(t *testing.T) {
+		random := rand.New(rand.NewSource(1))
+		index := NewSpatialIndex()
+		all := map[ShapeID]Shape{}
+		for i := 0; i < 2000; i++ {
+			shape := randomShape(random, 100)
+			id, err := index.Insert(shape)
+			if err != nil {
+				t.Fatal(err)
+			}
+			all[id] = shape
+		}
+		for id := range all {
+			if random.Intn(3) == 0 {
+				if err := index.Delete(id); err != nil {
+					t.Fatal(err)
+				}
+				delete(all, id)
+			}
+		}
+		assertTreeInvariants(t, index)
+		if index.Len() != len(all) {
+			t.Fatalf("got %d shapes want %d", index.Len(), len(all))
+		}
+
+		for i := 0; i < 50; i++ {
+			corner := Point{random.Float64() * 100, random.Float64() * 100}
+			area := Box{corner, Point{corner.X + random.Float64()*20, corner.Y + random.Float64()*20}}
+			if got, want := index.Search(area), bruteSearch(all, area); !reflect.DeepEqual(got, want) {
+				t.Errorf("search %v: got %v want %v", area, got, want)
+			}
+
+			p := Point{random.Float64() * 100, random.Float64() * 100}
+			if got, want := index.At(p), bruteAt(all, p); !reflect.DeepEqual(got, want) {
+				t.Errorf("at %v: got %v want %v", p, got, want)
+			}
+
+			got, want := index.Nearest(p, 5), bruteNearest(all, p, 5)
+			if len(got) != len(want) {
+				t.Fatalf("nearest %v: got %v want %v", p, got, want)
+			}
+			for j := range got {
+				if !almostEqual(got[j].Distance, want[j].Distance) {
+					t.Errorf("nearest %v: got %v want %v", p, got, want)
+					break
+				}
+			}
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		index := NewSpatialIndex()
+		id, _ := index.Insert(Square{1})
+
+		if err := index.Delete(id); err != nil {
+			t.Fatal(err)
+		}
+		if err := index.Delete(id); !errors.Is(err, ErrShapeNotFound) {
+			t.Errorf("got %v want %v", err, ErrShapeNotFound)
+		}
+		if _, ok := index.Shape(id); ok {
+			t.Error("expected the shape to be gone")
+		}
+		if got := index.At(Point{0.5, 0.5}); len(got) != 0 {
+			t.Errorf("got %v want nothing", got)
+		}
+	})
+
+	t.Run("emptying and refilling", func(t *testing.T) {
+		random := rand.New(rand.NewSource(2))
+		index := NewSpatialIndex()
+		var ids []ShapeID
+		for i := 0; i < 500; i++ {
+			id, _ := index.Insert(randomShape(random, 50))
+			ids = append(ids, id)
+		}
+		for _, id := range ids {
+			if err := index.Delete(id); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if index.Len() != 0 || len(index.Nearest(Point{}, 3)) != 0 {
+			t.Fatal("expected an empty index")
+		}
+
+		id, _ := index.Insert(Place(Circle{1}, Translate(10, 10)))
+		want := []Neighbour{{id, math.Sqrt(200) - 1}}
+		if got := index.Nearest(Point{}, 3); len(got) != 1 || got[0].ID != id || !almostEqual(got[0].Distance, want[0].Distance) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("unknown shapes", func(t *testing.T) {
+		if _, err := NewSpatialIndex().Insert(blob{}); !errors.Is(err, ErrUnknownShape) {
+			t.Errorf("got %v want %v", err, ErrUnknownShape)
+		}
+	})
+
+	t.Run("invalid shapes", func(t *testing.T) {
+		index := NewSpatialIndex()
+		if _, err := index.Insert(Polygon{}); !errors.Is(err, ErrDegeneratePolygon) {
+			t.Errorf("got %v want %v", err, ErrDegeneratePolygon)
+		}
+		if _, err := index.Insert(Circle{-1}); !errors.Is(err, ErrInvalidDimension) {
+			t.Errorf("got %v want %v", err, ErrInvalidDimension)
+		}
+		if index.Len() != 0 || len(index.Nearest(Point{0, 0}, 1)) != 0 {
+			t.Errorf("got %d shapes want an empty index", index.Len())
+		}
+	})
+}
+
+// assertTreeInvariants checks every node's box holds its children, every
+// node but the root is at least minimally full, and all leaves are at the
+// same depth.
+func assertTreeInvariants(t *testing.T, index *SpatialIndex) {
+	t.Helper()
+	leafDepth := -1
+	var check func(n *rtreeNode, depth int)
+	check = func(n *rtreeNode, depth int) {
+		if n != index.root && (len(n.children) < minNodeEntries || len(n.children) > maxNodeEntries) {
+			t.Errorf("node at depth %d has %d children", depth, len(n.children))
+		}
+		for _, child := range n.children {
+			if child.parent != n {
+				t.Errorf("child at depth %d has the wrong parent", depth+1)
+			}
+			if !n.box.ContainsBox(child.box) {
+				t.Errorf("box %v does not hold child box %v", n.box, child.box)
+			}
+			if !n.leaf {
+				check(child, depth+1)
+			}
+		}
+		if n.leaf {
+			if leafDepth == -1 {
+				leafDepth = depth
+			} else if depth != leafDepth {
+				t.Errorf("leaves at depths %d and %d", leafDepth, depth)
+			}
+		}
+	}
+	check(index.root, 0)
+}
+
+func randomShape(random *rand.Rand, size float64) Shape {
+	at := Translate(random.Float64()*size, random.Float64()*size)
+	switch random.Intn(3) {
+	case 0:
+		return Place(Rectangle{random.Float64() + 0.1, random.Float64() + 0.1}, Rotate(random.Float64()).Then(at))
+	case 1:
+		return Place(Circle{random.Float64()/2 + 0.1}, at)
+	default:
+		return Place(RegularPolygon{3 + random.Intn(5), random.Float64()/2 + 0.1}, at)
+	}
+}
+
+func bruteSearch(shapes map[ShapeID]Shape, area Box) []ShapeID {
+	query, _ := OutlineOf(area.Rectangle())
+	var ids []ShapeID
+	for id, shape := range shapes {
+		outline, _ := OutlineOf(shape)
+		if outlinesIntersect(outline, query) {
+			ids = append(ids, id)
+		}
+	}
+	sortIDs(ids)
+	return ids
+}
+
+func bruteAt(shapes map[ShapeID]Shape, p Point) []ShapeID {
+	var ids []ShapeID
+	for id, shape := range shapes {
+		r, _ := regionOf(shape)
+		if r.contains(p, epsilon) {
+			ids = append(ids, id)
+		}
+	}
+	sortIDs(ids)
+	return ids
+}
+
+func bruteNearest(shapes map[ShapeID]Shape, p Point, k int) []Neighbour {
+	var all []Neighbour
+	for id, shape := range shapes {
+		r, _ := regionOf(shape)
+		all = append(all, Neighbour{id, math.Max(0, r.signedDistance(p))})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Distance < all[j].Distance })
+	if len(all) > k {
+		all = all[:k]
+	}
+	return all
+}
+
+const benchmarkShapes = 100000
+
+var benchmarkIndex *SpatialIndex
+var benchmarkAll map[ShapeID]Shape
+
+func benchmarkData(b *testing.B) (*SpatialIndex, map[ShapeID]Shape) {
+	if benchmarkIndex == nil {
+		random := rand.New(rand.NewSource(3))
+		benchmarkIndex, benchmarkAll = NewSpatialIndex(), map[ShapeID]Shape{}
+		for i := 0; i < benchmarkShapes; i++ {
+			shape := randomShape(random, 1000)
+			id, _ := benchmarkIndex.Insert(shape)
+			benchmarkAll[id] = shape
+		}
+	}
+	b.ResetTimer()
+	return benchmarkIndex, benchmarkAll
+}
+
+func benchmarkQueries(n int) ([]Box, []Point) {
+	random := rand.New(rand.NewSource(4))
+	boxes, points := make([]Box, n), make([]Point, n)
+	for i := range boxes {
+		corner := Point{random.Float64() * 1000, random.Float64() * 1000}
+		boxes[i] = Box{corner, Point{corner.X + 10, corner.Y + 10}}
+		points[i] = Point{random.Float64() * 1000, random.Float64() * 1000}
+	}
+	return boxes, points
+}
+
+func BenchmarkSearch(b *testing.B) {
+	boxes, _ := benchmarkQueries(1024)
+	b.Run("index", func(b *testing.B) {
+		index, _ := benchmarkData(b)
+		for i := 0; i < b.N; i++ {
+			index.Search(boxes[i%len(boxes)])
+		}
+	})
+	b.Run("brute force", func(b *testing.B) {
+		_, all := benchmarkData(b)
+		for i := 0; i < b.N; i++ {
+			bruteSearch(all, boxes[i%len(boxes)])
+		}
+	})
+}
+
+func BenchmarkAt(b *testing.B) {
+	_, points := benchmarkQueries(1024)
+	b.Run("index", func(b *testing.B) {
+		index, _ := benchmarkData(b)
+		for i := 0; i < b.N; i++ {
+			index.At(points[i%len(points)])
+		}
+	})
+	b.Run("brute force", func(b *testing.B) {
+		_, all := benchmarkData(b)
+		for i := 0; i < b.N; i++ {
+			bruteAt(all, points[i%len(points)])
+		}
+	})
+}
+
+func BenchmarkNearest(b *testing.B) {
+	_, points := benchmarkQueries(1024)
+	b.Run("index", func(b *testing.B) {
+		index, _ := benchmarkData(b)
+		for i := 0; i < b.N; i++ {
+			index.Nearest(points[i%len(points)], 10)
+		}
+	})
+	b.Run("brute force", func(b *testing.B) {
+		_, all := benchmarkData(b)
+		for i := 0; i < b.N; i++ {
+			bruteNearest(all, points[i%len(points)], 10)
+		}
+	})
+}
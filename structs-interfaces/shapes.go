@@ -1,38 +1,224 @@
 package structs_interfaces
 
-import "math"
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrInvalidDimension = errors.New("invalid shape dimension")
 
 type Shape interface {
 	Area() float64
+	Perimeter() float64
+}
+
+// Validator is implemented by shapes that can check their own dimensions.
+type Validator interface {
+	Validate() error
+}
+
+// Validate checks the dimensions of shape, if it knows how to.
+func Validate(shape Shape) error {
+	if v, ok := shape.(Validator); ok {
+		return v.Validate()
+	}
+	return nil
 }
 
 type Rectangle struct {
-	Width  float64
-	Height float64
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+func NewRectangle(width, height float64) (Rectangle, error) {
+	r := Rectangle{width, height}
+	return r, r.Validate()
 }
 
 func (r Rectangle) Area() float64 {
 	return r.Width * r.Height
 }
 
+func (r Rectangle) Perimeter() float64 {
+	return 2 * (r.Width + r.Height)
+}
+
+func (r Rectangle) Validate() error {
+	return firstError(checkPositive("width", r.Width), checkPositive("height", r.Height))
+}
+
+type Square struct {
+	Side float64 `json:"side"`
+}
+
+func NewSquare(side float64) (Square, error) {
+	s := Square{side}
+	return s, s.Validate()
+}
+
+func (s Square) Area() float64 {
+	return s.Side * s.Side
+}
+
+func (s Square) Perimeter() float64 {
+	return 4 * s.Side
+}
+
+func (s Square) Validate() error {
+	return checkPositive("side", s.Side)
+}
+
 type Circle struct {
-	Radius float64
+	Radius float64 `json:"radius"`
+}
+
+func NewCircle(radius float64) (Circle, error) {
+	c := Circle{radius}
+	return c, c.Validate()
 }
 
 func (c Circle) Area() float64 {
 	return math.Pi * c.Radius * c.Radius
 }
 
+func (c Circle) Perimeter() float64 {
+	return 2 * math.Pi * c.Radius
+}
+
+func (c Circle) Validate() error {
+	return checkPositive("radius", c.Radius)
+}
+
+// Ellipse has semi-axes RadiusX and RadiusY.
+type Ellipse struct {
+	RadiusX float64 `json:"radius_x"`
+	RadiusY float64 `json:"radius_y"`
+}
+
+func NewEllipse(radiusX, radiusY float64) (Ellipse, error) {
+	e := Ellipse{radiusX, radiusY}
+	return e, e.Validate()
+}
+
+func (e Ellipse) Area() float64 {
+	return math.Pi * e.RadiusX * e.RadiusY
+}
+
+// Perimeter uses Ramanujan's second approximation, which is exact for circles
+// and within a few parts per million for all but extremely flat ellipses.
+func (e Ellipse) Perimeter() float64 {
+	a, b := e.RadiusX, e.RadiusY
+	h := (a - b) * (a - b) / ((a + b) * (a + b))
+	return math.Pi * (a + b) * (1 + 3*h/(10+math.Sqrt(4-3*h)))
+}
+
+func (e Ellipse) Validate() error {
+	return firstError(checkPositive("x radius", e.RadiusX), checkPositive("y radius", e.RadiusY))
+}
+
+// Triangle is given by its base and height, which fix its area but not the
+// lengths of its other two sides. It is drawn with the apex above the middle
+// of the base. Use SidedTriangle for a triangle with a known perimeter.
 type Triangle struct {
-	Base   float64
-	Height float64
+	Base   float64 `json:"base"`
+	Height float64 `json:"height"`
+}
+
+func NewTriangle(base, height float64) (Triangle, error) {
+	t := Triangle{base, height}
+	return t, t.Validate()
 }
 
 func (t Triangle) Area() float64 {
 	return (t.Base * t.Height) * 0.5
 }
 
-func Perimeter(rectangle Rectangle) float64 {
-	return 2 * (rectangle.Width + rectangle.Height)
+// Perimeter is NaN, as the base and height leave it open.
+func (t Triangle) Perimeter() float64 {
+	return math.NaN()
+}
+
+func (t Triangle) Validate() error {
+	return firstError(checkPositive("base", t.Base), checkPositive("height", t.Height))
 }
 
+// SidedTriangle is a triangle given by the lengths of its three sides.
+type SidedTriangle struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+	C float64 `json:"c"`
+}
+
+func NewSidedTriangle(a, b, c float64) (SidedTriangle, error) {
+	t := SidedTriangle{a, b, c}
+	return t, t.Validate()
+}
+
+// Area uses Heron's formula.
+func (t SidedTriangle) Area() float64 {
+	s := t.Perimeter() / 2
+	return math.Sqrt(s * (s - t.A) * (s - t.B) * (s - t.C))
+}
+
+func (t SidedTriangle) Perimeter() float64 {
+	return t.A + t.B + t.C
+}
+
+func (t SidedTriangle) Validate() error {
+	if err := firstError(checkPositive("a", t.A), checkPositive("b", t.B), checkPositive("c", t.C)); err != nil {
+		return err
+	}
+	if t.A+t.B <= t.C || t.A+t.C <= t.B || t.B+t.C <= t.A {
+		return fmt.Errorf("%w: sides %g, %g and %g do not form a triangle", ErrInvalidDimension, t.A, t.B, t.C)
+	}
+	return nil
+}
+
+// RegularPolygon has Sides sides, each SideLength long.
+type RegularPolygon struct {
+	Sides      int     `json:"sides"`
+	SideLength float64 `json:"side_length"`
+}
+
+func NewRegularPolygon(sides int, sideLength float64) (RegularPolygon, error) {
+	p := RegularPolygon{sides, sideLength}
+	return p, p.Validate()
+}
+
+func (p RegularPolygon) Area() float64 {
+	n := float64(p.Sides)
+	return n * p.SideLength * p.SideLength / (4 * math.Tan(math.Pi/n))
+}
+
+func (p RegularPolygon) Perimeter() float64 {
+	return float64(p.Sides) * p.SideLength
+}
+
+func (p RegularPolygon) Validate() error {
+	if p.Sides < 3 {
+		return fmt.Errorf("%w: a polygon needs at least 3 sides, got %d", ErrInvalidDimension, p.Sides)
+	}
+	return checkPositive("side length", p.SideLength)
+}
+
+func Perimeter(shape Shape) float64 {
+	return shape.Perimeter()
+}
+
+// checkPositive fails unless value is a positive finite number.
+func checkPositive(name string, value float64) error {
+	if !(value > 0) || math.IsInf(value, 0) {
+		return fmt.Errorf("%w: %s must be a positive number, got %g", ErrInvalidDimension, name, value)
+	}
+	return nil
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package structs_interfaces
 
-import "testing"
+import (
+	"errors"
+	"math"
+	"testing"
+)
 
 func TestPerimeter(t *testing.T) {
 	rectangle := Rectangle{10.0, 10.0}
@@ -30,3 +34,92 @@ func TestArea(t *testing.T) {
 		}
 	}
 }
+func TestShapePerimeter(t *testing.T) {
+	perimeterTests := []struct {
+		shape Shape
+		want  float64
+	}{
+		{shape: Rectangle{12, 6}, want: 36.0},
+		{shape: Square{5}, want: 20.0},
+		{shape: Circle{10}, want: 62.83185307179586},
+		{shape: Ellipse{10, 10}, want: 62.83185307179586},
+		{shape: Ellipse{10, 5}, want: 48.44224110273838},
+		{shape: SidedTriangle{3, 4, 5}, want: 12.0},
+		{shape: RegularPolygon{6, 2}, want: 12.0},
+	}
+
+	for _, tt := range perimeterTests {
+		got := Perimeter(tt.shape)
+		if !almostEqual(got, tt.want) {
+			t.Errorf("%#v got %g want %g", tt.shape, got, tt.want)
+		}
+	}
+
+	t.Run("base and height leave the perimeter open", func(t *testing.T) {
+		shapes := []Shape{
+			Triangle{6, 4},
+			Place(Triangle{6, 4}, Rotate(1)),
+			Union(Square{1}, Triangle{6, 4}),
+		}
+		for _, shape := range shapes {
+			if got := shape.Perimeter(); !math.IsNaN(got) {
+				t.Errorf("%#v got %g want NaN", shape, got)
+			}
+		}
+	})
+}
+
+func TestNewShapeArea(t *testing.T) {
+	areaTests := []struct {
+		shape Shape
+		want  float64
+	}{
+		{shape: Square{5}, want: 25.0},
+		{shape: Ellipse{10, 5}, want: 157.07963267948966},
+		{shape: SidedTriangle{3, 4, 5}, want: 6.0},
+		{shape: RegularPolygon{4, 3}, want: 9.0},
+		{shape: RegularPolygon{6, 2}, want: 10.392304845413264},
+	}
+
+	for _, tt := range areaTests {
+		got := tt.shape.Area()
+		if !almostEqual(got, tt.want) {
+			t.Errorf("%#v got %g want %g", tt.shape, got, tt.want)
+		}
+	}
+}
+
+func TestInvalidDimensions(t *testing.T) {
+	constructors := map[string]func() (Shape, error){
+		"negative width":      func() (Shape, error) { return NewRectangle(-1, 2) },
+		"NaN height":          func() (Shape, error) { return NewRectangle(1, math.NaN()) },
+		"zero side":           func() (Shape, error) { return NewSquare(0) },
+		"infinite radius":     func() (Shape, error) { return NewCircle(math.Inf(1)) },
+		"negative radius":     func() (Shape, error) { return NewEllipse(1, -1) },
+		"zero height":         func() (Shape, error) { return NewTriangle(3, 0) },
+		"impossible triangle": func() (Shape, error) { return NewSidedTriangle(1, 2, 3) },
+		"too few sides":       func() (Shape, error) { return NewRegularPolygon(2, 1) },
+	}
+
+	for name, construct := range constructors {
+		t.Run(name, func(t *testing.T) {
+			_, err := construct()
+			if !errors.Is(err, ErrInvalidDimension) {
+				t.Errorf("got %v want %v", err, ErrInvalidDimension)
+			}
+		})
+	}
+
+	t.Run("valid shapes", func(t *testing.T) {
+		if _, err := NewSidedTriangle(3, 4, 5); err != nil {
+			t.Error(err)
+		}
+		if err := Validate(Rectangle{12, 6}); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
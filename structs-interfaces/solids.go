@@ -0,0 +1,140 @@
+package structs_interfaces
+
+import (
+	"fmt"
+	"math"
+)
+
+type Solid interface {
+	Volume() float64
+	SurfaceArea() float64
+}
+
+// Prism extrudes any Shape by Height: its volume is the base area times the
+// height, and its surface is the two ends plus the sides.
+type Prism struct {
+	Base   Shape   `json:"base"`
+	Height float64 `json:"height"`
+}
+
+func NewPrism(base Shape, height float64) (Prism, error) {
+	p := Prism{base, height}
+	return p, p.Validate()
+}
+
+func (p Prism) Volume() float64 {
+	return p.Base.Area() * p.Height
+}
+
+func (p Prism) SurfaceArea() float64 {
+	return 2*p.Base.Area() + p.Base.Perimeter()*p.Height
+}
+
+func (p Prism) Validate() error {
+	if p.Base == nil {
+		return fmt.Errorf("%w: prism has no base", ErrInvalidDimension)
+	}
+	return firstError(Validate(p.Base), checkPositive("height", p.Height))
+}
+
+type Cuboid struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Depth  float64 `json:"depth"`
+}
+
+func NewCuboid(width, height, depth float64) (Cuboid, error) {
+	c := Cuboid{width, height, depth}
+	return c, c.Validate()
+}
+
+func (c Cuboid) Volume() float64 {
+	return c.prism().Volume()
+}
+
+func (c Cuboid) SurfaceArea() float64 {
+	return c.prism().SurfaceArea()
+}
+
+func (c Cuboid) Validate() error {
+	return firstError(checkPositive("width", c.Width), checkPositive("height", c.Height), checkPositive("depth", c.Depth))
+}
+
+func (c Cuboid) prism() Prism {
+	return Prism{Rectangle{c.Width, c.Depth}, c.Height}
+}
+
+type Cylinder struct {
+	Radius float64 `json:"radius"`
+	Height float64 `json:"height"`
+}
+
+func NewCylinder(radius, height float64) (Cylinder, error) {
+	c := Cylinder{radius, height}
+	return c, c.Validate()
+}
+
+func (c Cylinder) Volume() float64 {
+	return c.prism().Volume()
+}
+
+func (c Cylinder) SurfaceArea() float64 {
+	return c.prism().SurfaceArea()
+}
+
+func (c Cylinder) Validate() error {
+	return firstError(checkPositive("radius", c.Radius), checkPositive("height", c.Height))
+}
+
+func (c Cylinder) prism() Prism {
+	return Prism{Circle{c.Radius}, c.Height}
+}
+
+// Cone is a right circular cone.
+type Cone struct {
+	Radius float64 `json:"radius"`
+	Height float64 `json:"height"`
+}
+
+func NewCone(radius, height float64) (Cone, error) {
+	c := Cone{radius, height}
+	return c, c.Validate()
+}
+
+func (c Cone) Volume() float64 {
+	return Circle{c.Radius}.Area() * c.Height / 3
+}
+
+// SurfaceArea is the base plus the curved side, which unrolls into a sector
+// of a circle whose radius is the slant height.
+func (c Cone) SurfaceArea() float64 {
+	base := Circle{c.Radius}
+	slant := math.Hypot(c.Radius, c.Height)
+	return base.Area() + base.Perimeter()*slant/2
+}
+
+func (c Cone) Validate() error {
+	return firstError(checkPositive("radius", c.Radius), checkPositive("height", c.Height))
+}
+
+type Sphere struct {
+	Radius float64 `json:"radius"`
+}
+
+func NewSphere(radius float64) (Sphere, error) {
+	s := Sphere{radius}
+	return s, s.Validate()
+}
+
+func (s Sphere) Volume() float64 {
+	return 4 * math.Pi * s.Radius * s.Radius * s.Radius / 3
+}
+
+// SurfaceArea is four times the area of the sphere's great circle.
+func (s Sphere) SurfaceArea() float64 {
+	return 4 * Circle{s.Radius}.Area()
+}
+
+func (s Sphere) Validate() error {
+	return checkPositive("radius", s.Radius)
+}
@@ -0,0 +1,70 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSolids(t *testing.T) {
+	solidTests := []struct {
+		solid   Solid
+		volume  float64
+		surface float64
+	}{
+		{Cuboid{2, 3, 4}, 24, 52},
+		{Sphere{3}, 36 * math.Pi, 36 * math.Pi},
+		{Cylinder{2, 5}, 20 * math.Pi, 28 * math.Pi},
+		{Cone{3, 4}, 12 * math.Pi, 24 * math.Pi},
+		{Prism{Square{2}, 3}, 12, 32},
+		{Prism{SidedTriangle{3, 4, 5}, 10}, 60, 132},
+		{Prism{Polygon{lShape}, 2}, 12, 40},
+	}
+
+	for _, tt := range solidTests {
+		if got := tt.solid.Volume(); !almostEqual(got, tt.volume) {
+			t.Errorf("%#v got volume %g want %g", tt.solid, got, tt.volume)
+		}
+		if got := tt.solid.SurfaceArea(); !almostEqual(got, tt.surface) {
+			t.Errorf("%#v got surface area %g want %g", tt.solid, got, tt.surface)
+		}
+	}
+}
+
+func TestPrismMatchesSolids(t *testing.T) {
+	cylinder := Cylinder{1.5, 4}
+	prism := Prism{Circle{1.5}, 4}
+
+	if !almostEqual(cylinder.Volume(), prism.Volume()) || !almostEqual(cylinder.SurfaceArea(), prism.SurfaceArea()) {
+		t.Errorf("cylinder %g, %g and prism %g, %g differ",
+			cylinder.Volume(), cylinder.SurfaceArea(), prism.Volume(), prism.SurfaceArea())
+	}
+
+	placed := Prism{Place(Rectangle{2, 3}, Rotate(0.4).Then(Translate(5, 5))), 4}
+	cuboid := Cuboid{2, 4, 3}
+	if !almostEqual(placed.Volume(), cuboid.Volume()) || !almostEqual(placed.SurfaceArea(), cuboid.SurfaceArea()) {
+		t.Errorf("placed prism %g, %g and cuboid %g, %g differ",
+			placed.Volume(), placed.SurfaceArea(), cuboid.Volume(), cuboid.SurfaceArea())
+	}
+}
+
+func TestInvalidSolids(t *testing.T) {
+	constructors := map[string]func() (Solid, error){
+		"zero depth":      func() (Solid, error) { return NewCuboid(1, 1, 0) },
+		"negative radius": func() (Solid, error) { return NewSphere(-1) },
+		"NaN height":      func() (Solid, error) { return NewCylinder(1, math.NaN()) },
+		"infinite cone":   func() (Solid, error) { return NewCone(1, math.Inf(1)) },
+		"no base":         func() (Solid, error) { return NewPrism(nil, 1) },
+		"invalid base":    func() (Solid, error) { return NewPrism(Circle{-1}, 1) },
+		"flat prism":      func() (Solid, error) { return NewPrism(Square{1}, 0) },
+	}
+
+	for name, construct := range constructors {
+		t.Run(name, func(t *testing.T) {
+			_, err := construct()
+			if !errors.Is(err, ErrInvalidDimension) {
+				t.Errorf("got %v want %v", err, ErrInvalidDimension)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package structs_interfaces
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Style is how a shape is drawn. Empty colours fall back to a black stroke and
+// no fill, and a zero StrokeWidth to 1.
+type Style struct {
+	Stroke      string
+	Fill        string
+	StrokeWidth float64
+	Label       string
+}
+
+// Figure is a shape with the style to draw it in.
+type Figure struct {
+	Shape Shape
+	Style Style
+}
+
+type SVGOptions struct {
+	// Margin is added around the shapes when fitting the viewBox.
+	Margin float64
+	// Width and Height set the size of the document, e.g. "800px". The
+	// viewBox is used if they are empty.
+	Width  string
+	Height string
+}
+
+// WriteSVG draws figures into an SVG document whose viewBox fits them all.
+// Shapes use their outline coordinates, with y pointing up as in the rest of
+// the package, so they are mirrored into SVG's y-down space.
+func WriteSVG(w io.Writer, figures []Figure, options SVGOptions) error {
+	outlines, box, err := figureOutlines(figures)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%s %s %s %s"`,
+		svgNumber(box.Min.X-options.Margin), svgNumber(-box.Max.Y-options.Margin),
+		svgNumber(box.Width()+2*options.Margin), svgNumber(box.Height()+2*options.Margin))
+	if options.Width != "" {
+		fmt.Fprintf(out, ` width="%s"`, escapeXML(options.Width))
+	}
+	if options.Height != "" {
+		fmt.Fprintf(out, ` height="%s"`, escapeXML(options.Height))
+	}
+	out.WriteString(">\n")
+
+	for i, f := range figures {
+		writeSVGShape(out, outlines[i], f.Style)
+	}
+	for i, f := range figures {
+		if f.Style.Label != "" && len(outlines[i]) > 0 {
+			centre := newRegion(outlines[i]).box.Centre()
+			fmt.Fprintf(out, `  <text x="%s" y="%s" text-anchor="middle" dominant-baseline="middle">%s</text>`+"\n",
+				svgNumber(centre.X), svgNumber(-centre.Y), escapeXML(f.Style.Label))
+		}
+	}
+
+	out.WriteString("</svg>\n")
+	return out.Flush()
+}
+
+// figureOutlines returns the outlines of each figure, see OutlinesOf, and a
+// box holding them all, strokes included.
+func figureOutlines(figures []Figure) ([][]Outline, Box, error) {
+	outlines := make([][]Outline, len(figures))
+	var box Box
+	empty := true
+	for i, f := range figures {
+		r, err := regionOf(f.Shape)
+		if err != nil {
+			return nil, Box{}, fmt.Errorf("figure %d: %w", i, err)
+		}
+		outlines[i] = r.parts
+		if len(r.parts) == 0 {
+			continue
+		}
+
+		half := strokeWidth(f.Style) / 2
+		bounds := Box{Point{r.box.Min.X - half, r.box.Min.Y - half}, Point{r.box.Max.X + half, r.box.Max.Y + half}}
+		if empty {
+			box, empty = bounds, false
+		} else {
+			box = box.Union(bounds)
+		}
+	}
+	return outlines, box, nil
+}
+
+// writeSVGShape draws a shape with several parts as one path, so its holes
+// are left unfilled.
+func writeSVGShape(out *bufio.Writer, outlines []Outline, style Style) {
+	paint := fmt.Sprintf(`stroke="%s" fill="%s" stroke-width="%s"`,
+		escapeXML(orDefault(style.Stroke, "black")), escapeXML(orDefault(style.Fill, "none")), svgNumber(strokeWidth(style)))
+
+	if len(outlines) != 1 {
+		var d []string
+		for _, outline := range outlines {
+			for i, p := range outline.Points {
+				command := "L"
+				if i == 0 {
+					command = "M"
+				}
+				d = append(d, command+svgNumber(p.X)+","+svgNumber(-p.Y))
+			}
+			d = append(d, "Z")
+		}
+		if len(d) > 0 {
+			fmt.Fprintf(out, `  <path d="%s" fill-rule="nonzero" %s/>`+"\n", strings.Join(d, " "), paint)
+		}
+		return
+	}
+
+	outline := outlines[0]
+	if !outline.Curved {
+		points := make([]string, len(outline.Points))
+		for i, p := range outline.Points {
+			points[i] = svgNumber(p.X) + "," + svgNumber(-p.Y)
+		}
+		fmt.Fprintf(out, `  <polygon points="%s" %s/>`+"\n", strings.Join(points, " "), paint)
+		return
+	}
+
+	m := outline.Curve
+	if m.scalesEvenly() {
+		radius, _ := m.Stretch()
+		fmt.Fprintf(out, `  <circle cx="%s" cy="%s" r="%s" %s/>`+"\n", svgNumber(m.E), svgNumber(-m.F), svgNumber(radius), paint)
+		return
+	}
+
+	// The major axis of the mapped circle lies along the eigenvector of
+	// L·Lᵀ with the largest eigenvalue, L being the linear part of m.
+	rx, ry := m.Stretch()
+	angle := 0.5 * math.Atan2(2*(m.A*m.B+m.C*m.D), m.A*m.A+m.C*m.C-m.B*m.B-m.D*m.D)
+	fmt.Fprintf(out, `  <ellipse cx="%s" cy="%s" rx="%s" ry="%s" transform="rotate(%s %s %s)" %s/>`+"\n",
+		svgNumber(m.E), svgNumber(-m.F), svgNumber(rx), svgNumber(ry),
+		svgNumber(-angle*180/math.Pi), svgNumber(m.E), svgNumber(-m.F), paint)
+}
+
+func strokeWidth(style Style) float64 {
+	if style.StrokeWidth > 0 {
+		return style.StrokeWidth
+	}
+	return 1
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// svgNumber rounds to four decimal places so output is stable and readable.
+func svgNumber(v float64) string {
+	v = math.Round(v*1e4) / 1e4
+	if v == 0 {
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package structs_interfaces
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestWriteSVG(t *testing.T) {
+	svgTests := []struct {
+		name    string
+		figures []Figure
+		options SVGOptions
+	}{
+		{
+			name: "basic_shapes",
+			figures: []Figure{
+				{Shape: Rectangle{4, 2}, Style: Style{Fill: "lightblue", Label: "room"}},
+				{Shape: Place(Circle{1}, Translate(6, 1)), Style: Style{Stroke: "red", StrokeWidth: 0.2}},
+				{Shape: Place(Triangle{2, 2}, Translate(0, 3))},
+			},
+			options: SVGOptions{Margin: 1},
+		},
+		{
+			name: "transformed_shapes",
+			figures: []Figure{
+				{Shape: Place(Ellipse{3, 1}, Rotate(math.Pi/6)), Style: Style{Fill: "#ccc", Label: "a < b & c"}},
+				{Shape: Place(Square{2}, Rotate(math.Pi/4).Then(Translate(5, 0)))},
+				{Shape: Place(Polygon{lShape}, Scale(-1, 1)), Style: Style{Stroke: "green"}},
+			},
+			options: SVGOptions{Width: "400px", Height: "300px"},
+		},
+	}
+
+	for _, tt := range svgTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bytes.Buffer
+			if err := WriteSVG(&got, tt.figures, tt.options); err != nil {
+				t.Fatal(err)
+			}
+			assertGolden(t, filepath.Join("testdata", tt.name+".svg"), got.Bytes())
+		})
+	}
+
+	t.Run("unknown shapes", func(t *testing.T) {
+		err := WriteSVG(ioutil.Discard, []Figure{{Shape: blob{}}}, SVGOptions{})
+		if !errors.Is(err, ErrUnknownShape) {
+			t.Errorf("got %v want %v", err, ErrUnknownShape)
+		}
+	})
+}
+
+func assertGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal("could not read golden file, run with -update to create it:", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,284 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrSingularMatrix = errors.New("matrix cannot be inverted")
+
+// Matrix is a 2D affine transform. It maps (x, y) to
+//
+//	(A*x + C*y + E, B*x + D*y + F)
+//
+// which is the same order SVG uses for matrix(a, b, c, d, e, f).
+type Matrix struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+	C float64 `json:"c"`
+	D float64 `json:"d"`
+	E float64 `json:"e"`
+	F float64 `json:"f"`
+}
+
+var Identity = Matrix{A: 1, D: 1}
+
+func Translate(dx, dy float64) Matrix {
+	return Matrix{A: 1, D: 1, E: dx, F: dy}
+}
+
+// Rotate turns counter-clockwise by angle radians about the origin.
+func Rotate(angle float64) Matrix {
+	sin, cos := math.Sincos(angle)
+	return Matrix{A: cos, B: sin, C: -sin, D: cos}
+}
+
+// RotateAbout turns counter-clockwise by angle radians about centre.
+func RotateAbout(angle float64, centre Point) Matrix {
+	return Translate(-centre.X, -centre.Y).Then(Rotate(angle)).Then(Translate(centre.X, centre.Y))
+}
+
+func Scale(sx, sy float64) Matrix {
+	return Matrix{A: sx, D: sy}
+}
+
+// Then returns the transform that applies m and then n.
+func (m Matrix) Then(n Matrix) Matrix {
+	return Matrix{
+		A: n.A*m.A + n.C*m.B,
+		B: n.B*m.A + n.D*m.B,
+		C: n.A*m.C + n.C*m.D,
+		D: n.B*m.C + n.D*m.D,
+		E: n.A*m.E + n.C*m.F + n.E,
+		F: n.B*m.E + n.D*m.F + n.F,
+	}
+}
+
+func (m Matrix) Apply(p Point) Point {
+	return Point{m.A*p.X + m.C*p.Y + m.E, m.B*p.X + m.D*p.Y + m.F}
+}
+
+// Determinant is the factor the transform scales areas by, negative when it
+// mirrors.
+func (m Matrix) Determinant() float64 {
+	return m.A*m.D - m.B*m.C
+}
+
+func (m Matrix) Invert() (Matrix, error) {
+	det := m.Determinant()
+	if math.Abs(det) < epsilon {
+		return Matrix{}, ErrSingularMatrix
+	}
+	return Matrix{
+		A: m.D / det,
+		B: -m.B / det,
+		C: -m.C / det,
+		D: m.A / det,
+		E: (m.C*m.F - m.D*m.E) / det,
+		F: (m.B*m.E - m.A*m.F) / det,
+	}, nil
+}
+
+// Stretch returns the largest and smallest factors the transform scales
+// lengths by, the singular values of its linear part.
+func (m Matrix) Stretch() (float64, float64) {
+	e, f := (m.A+m.D)/2, (m.A-m.D)/2
+	g, h := (m.B-m.C)/2, (m.B+m.C)/2
+	q, r := math.Hypot(e, g), math.Hypot(f, h)
+	return q + r, math.Abs(q - r)
+}
+
+// scalesEvenly reports whether the transform scales lengths by the same factor
+// in every direction, so it keeps circles circular.
+func (m Matrix) scalesEvenly() bool {
+	major, minor := m.Stretch()
+	return major-minor <= epsilon*math.Max(1, major)
+}
+
+// Outline is the boundary of a shape. A polygonal shape has Points,
+// counter-clockwise; a curved shape is the unit circle mapped by Curve.
+type Outline struct {
+	Points []Point
+	Curve  Matrix
+	Curved bool
+}
+
+func (o Outline) Transform(m Matrix) Outline {
+	if o.Curved {
+		return Outline{Curve: o.Curve.Then(m), Curved: true}
+	}
+	points := make([]Point, len(o.Points))
+	for i, p := range o.Points {
+		points[i] = m.Apply(p)
+	}
+	if m.Determinant() < 0 {
+		reverse(points)
+	}
+	return Outline{Points: points}
+}
+
+// OutlineOf returns the outline of shape in its own coordinates. Rectangles,
+// squares and triangles sit with their first corner on the origin and their
+// base along the x axis, like an SVG rect. Circles, ellipses and regular
+// polygons are centred on the origin.
+func OutlineOf(shape Shape) (Outline, bool) {
+	switch s := shape.(type) {
+	case Placed:
+		outline, ok := OutlineOf(s.Shape)
+		return outline.Transform(s.Transform), ok
+	case Circle:
+		return Outline{Curve: Scale(s.Radius, s.Radius), Curved: true}, true
+	case Ellipse:
+		return Outline{Curve: Scale(s.RadiusX, s.RadiusY), Curved: true}, true
+	case Rectangle:
+		return Outline{Points: []Point{{0, 0}, {s.Width, 0}, {s.Width, s.Height}, {0, s.Height}}}, true
+	case Square:
+		return Outline{Points: []Point{{0, 0}, {s.Side, 0}, {s.Side, s.Side}, {0, s.Side}}}, true
+	case Triangle:
+		return Outline{Points: []Point{{0, 0}, {s.Base, 0}, {s.Base / 2, s.Height}}}, true
+	case SidedTriangle:
+		// A runs along the x axis, B joins its end to the apex and C joins
+		// the apex back to the origin.
+		x := (s.A*s.A + s.C*s.C - s.B*s.B) / (2 * s.A)
+		return Outline{Points: []Point{{0, 0}, {s.A, 0}, {x, math.Sqrt(s.C*s.C - x*x)}}}, true
+	case RegularPolygon:
+		return Outline{Points: regularPolygonPoints(s)}, true
+	case Polygon:
+		points := append([]Point(nil), s.Points...)
+		if s.Orientation() == Clockwise {
+			reverse(points)
+		}
+		return Outline{Points: points}, true
+	default:
+		return Outline{}, false
+	}
+}
+
+// OutlinesOf returns the outlines of the parts of shape, in the same
+// coordinates as OutlineOf. Most shapes have one part. A MultiPolygon has one
+// for each polygon, and a Composite one for each loop of its boundary,
+// counter-clockwise around what it covers and clockwise around its holes, so
+// a point is inside the shape when the outlines wind around it a non-zero
+// number of times in all. A shape that covers nothing has no outlines.
+func OutlinesOf(shape Shape) ([]Outline, error) {
+	switch s := shape.(type) {
+	case Placed:
+		outlines, err := OutlinesOf(s.Shape)
+		for i, outline := range outlines {
+			outlines[i] = outline.Transform(s.Transform)
+		}
+		return outlines, err
+	case MultiPolygon:
+		outlines := make([]Outline, len(s.Polygons))
+		for i, polygon := range s.Polygons {
+			outlines[i], _ = OutlineOf(polygon)
+		}
+		return outlines, nil
+	case Composite:
+		pieces, err := s.pieces()
+		if err != nil {
+			return nil, err
+		}
+		rings := boundaryRings(pieces)
+		outlines := make([]Outline, len(rings))
+		for i, ring := range rings {
+			outlines[i] = Outline{Points: ring}
+		}
+		return outlines, nil
+	}
+
+	outline, ok := OutlineOf(shape)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnknownShape, shape)
+	}
+	return []Outline{outline}, nil
+}
+
+// regularPolygonPoints are centred on the origin with the bottom side
+// horizontal.
+func regularPolygonPoints(p RegularPolygon) []Point {
+	if p.Sides < 3 {
+		return nil
+	}
+	n := float64(p.Sides)
+	radius := p.SideLength / (2 * math.Sin(math.Pi/n))
+	start := -math.Pi/2 - math.Pi/n
+	points := make([]Point, p.Sides)
+	for i := range points {
+		sin, cos := math.Sincos(start + 2*math.Pi*float64(i)/n)
+		points[i] = Point{radius * cos, radius * sin}
+	}
+	return points
+}
+
+// Placed is a shape positioned in the plane by transforming its own
+// coordinates.
+type Placed struct {
+	Shape     Shape  `json:"shape"`
+	Transform Matrix `json:"transform"`
+}
+
+// Place positions shape with transform. Placing a Placed shape composes the
+// transforms.
+func Place(shape Shape, transform Matrix) Placed {
+	if p, ok := shape.(Placed); ok {
+		return Placed{p.Shape, p.Transform.Then(transform)}
+	}
+	return Placed{shape, transform}
+}
+
+func (p Placed) Translate(dx, dy float64) Placed {
+	return Place(p, Translate(dx, dy))
+}
+
+func (p Placed) Rotate(angle float64) Placed {
+	return Place(p, Rotate(angle))
+}
+
+func (p Placed) Scale(sx, sy float64) Placed {
+	return Place(p, Scale(sx, sy))
+}
+
+func (p Placed) Area() float64 {
+	return p.Shape.Area() * math.Abs(p.Transform.Determinant())
+}
+
+// Perimeter is exact for polygonal shapes and uses Ellipse.Perimeter for
+// curved ones. Other shapes only keep a known perimeter under transforms that
+// scale evenly; for anything else, or shapes with no known perimeter, it is
+// NaN.
+func (p Placed) Perimeter() float64 {
+	if math.IsNaN(p.Shape.Perimeter()) {
+		return math.NaN()
+	}
+	if outline, ok := OutlineOf(p); ok {
+		if outline.Curved {
+			major, minor := outline.Curve.Stretch()
+			return Ellipse{major, minor}.Perimeter()
+		}
+		return Polygon{outline.Points}.Perimeter()
+	}
+
+	if !p.Transform.scalesEvenly() {
+		return math.NaN()
+	}
+	scale, _ := p.Transform.Stretch()
+	return p.Shape.Perimeter() * scale
+}
+
+func (p Placed) Validate() error {
+	if p.Shape == nil {
+		return fmt.Errorf("%w: placed shape is missing", ErrInvalidDimension)
+	}
+	if _, err := p.Transform.Invert(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDimension, err)
+	}
+	return Validate(p.Shape)
+}
+
+func reverse(points []Point) {
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+}
@@ -0,0 +1,144 @@
+package structs_interfaces
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMatrix(t *testing.T) {
+	t.Run("compose", func(t *testing.T) {
+		m := Scale(2, 2).Then(Rotate(math.Pi / 2)).Then(Translate(1, 0))
+
+		assertPoint(t, m.Apply(Point{1, 0}), Point{1, 2})
+	})
+
+	t.Run("rotate about a point", func(t *testing.T) {
+		m := RotateAbout(math.Pi, Point{1, 1})
+
+		assertPoint(t, m.Apply(Point{0, 0}), Point{2, 2})
+	})
+
+	t.Run("invert", func(t *testing.T) {
+		m := Scale(2, 3).Then(Rotate(0.3)).Then(Translate(4, -1))
+		inverse, err := m.Invert()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertPoint(t, inverse.Apply(m.Apply(Point{5, 7})), Point{5, 7})
+
+		if _, err := Scale(0, 1).Invert(); err != ErrSingularMatrix {
+			t.Errorf("got %v want %v", err, ErrSingularMatrix)
+		}
+	})
+
+	t.Run("stretch", func(t *testing.T) {
+		major, minor := Scale(3, 2).Then(Rotate(1)).Stretch()
+
+		if !almostEqual(major, 3) || !almostEqual(minor, 2) {
+			t.Errorf("got %g, %g want 3, 2", major, minor)
+		}
+	})
+}
+
+func TestPlaced(t *testing.T) {
+	shapes := []Shape{
+		Rectangle{4, 2},
+		Square{3},
+		Circle{2},
+		Ellipse{3, 1},
+		Triangle{4, 3},
+		SidedTriangle{3, 4, 5},
+		RegularPolygon{5, 2},
+		Polygon{lShape},
+	}
+	transforms := []Matrix{
+		Translate(5, -3),
+		Rotate(0.7),
+		Scale(2, 2),
+		Scale(2, 0.5).Then(Rotate(1.1)).Then(Translate(-1, 4)),
+		Scale(-1, 1),
+	}
+
+	t.Run("area is consistent with the transformed outline", func(t *testing.T) {
+		for _, shape := range shapes {
+			for _, m := range transforms {
+				placed := Place(shape, m)
+				outline, ok := OutlineOf(placed)
+				if !ok {
+					t.Fatalf("%#v has no outline", shape)
+				}
+
+				want := math.Abs(shape.Area() * m.Determinant())
+				if !almostEqual(placed.Area(), want) {
+					t.Errorf("%#v under %v: got area %g want %g", shape, m, placed.Area(), want)
+				}
+				if !outline.Curved && !almostEqual(Polygon{outline.Points}.Area(), want) {
+					t.Errorf("%#v under %v: outline area %g want %g", shape, m, Polygon{outline.Points}.Area(), want)
+				}
+				if !outline.Curved && (Polygon{outline.Points}).Orientation() != CounterClockwise {
+					t.Errorf("%#v under %v: outline is not counter-clockwise", shape, m)
+				}
+			}
+		}
+	})
+
+	t.Run("outlines match the shape", func(t *testing.T) {
+		for _, shape := range shapes {
+			outline, _ := OutlineOf(shape)
+			if outline.Curved {
+				continue
+			}
+			polygon := Polygon{outline.Points}
+			if !almostEqual(polygon.Area(), shape.Area()) {
+				t.Errorf("%#v: outline area %g", shape, polygon.Area())
+			}
+			if perimeter := shape.Perimeter(); !math.IsNaN(perimeter) && !almostEqual(polygon.Perimeter(), perimeter) {
+				t.Errorf("%#v: outline perimeter %g want %g", shape, polygon.Perimeter(), perimeter)
+			}
+		}
+	})
+
+	t.Run("perimeter", func(t *testing.T) {
+		circle := Place(Circle{1}, Rotate(0.5)).Scale(2, 2).Translate(3, 3)
+		if want := 4 * math.Pi; !almostEqual(circle.Perimeter(), want) {
+			t.Errorf("got %g want %g", circle.Perimeter(), want)
+		}
+
+		stretched := Place(Circle{1}, Scale(3, 1))
+		if want := (Ellipse{3, 1}).Perimeter(); !almostEqual(stretched.Perimeter(), want) {
+			t.Errorf("got %g want %g", stretched.Perimeter(), want)
+		}
+
+		rectangle := Place(Rectangle{4, 2}, Scale(1, 3))
+		if !almostEqual(rectangle.Perimeter(), 20) {
+			t.Errorf("got %g want 20", rectangle.Perimeter())
+		}
+	})
+
+	t.Run("placing a placed shape composes", func(t *testing.T) {
+		placed := Place(Rectangle{1, 1}, Translate(1, 0)).Rotate(math.Pi / 2)
+
+		outline, _ := OutlineOf(placed)
+		assertPoint(t, outline.Points[0], Point{0, 1})
+		if _, nested := placed.Shape.(Placed); nested {
+			t.Error("expected transforms to be composed, not nested")
+		}
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		if err := Validate(Place(Rectangle{1, 1}, Scale(0, 1))); err == nil {
+			t.Error("expected a collapsed shape to be invalid")
+		}
+		if err := Validate(Place(Rectangle{-1, 1}, Identity)); err == nil {
+			t.Error("expected an invalid shape to stay invalid when placed")
+		}
+	})
+}
+
+func assertPoint(t *testing.T, got, want Point) {
+	t.Helper()
+	if !almostEqual(got.X, want.X) || !almostEqual(got.Y, want.Y) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
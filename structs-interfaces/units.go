@@ -0,0 +1,183 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var (
+	ErrMixedUnits  = errors.New("lengths are in different units")
+	ErrUnknownUnit = errors.New("unknown unit")
+)
+
+// Unit is a unit of length. The zero Unit is no unit at all, and is rejected
+// wherever a unit is needed.
+type Unit int
+
+const (
+	Millimetre Unit = iota + 1
+	Centimetre
+	Metre
+	Inch
+	Foot
+)
+
+var units = map[Unit]struct {
+	symbol string
+	metres float64
+}{
+	Millimetre: {"mm", 0.001},
+	Centimetre: {"cm", 0.01},
+	Metre:      {"m", 1},
+	Inch:       {"in", 0.0254},
+	Foot:       {"ft", 0.3048},
+}
+
+func ParseUnit(symbol string) (Unit, error) {
+	for u, info := range units {
+		if info.symbol == symbol {
+			return u, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, symbol)
+}
+
+func (u Unit) String() string {
+	if info, ok := units[u]; ok {
+		return info.symbol
+	}
+	return "Unit(" + strconv.Itoa(int(u)) + ")"
+}
+
+func (u Unit) valid() bool {
+	_, ok := units[u]
+	return ok
+}
+
+// per returns how many of to make one u.
+func (u Unit) per(to Unit) (float64, error) {
+	for _, unit := range []Unit{u, to} {
+		if !unit.valid() {
+			return 0, fmt.Errorf("%w: cannot convert between %v and %v", ErrUnknownUnit, u, to)
+		}
+	}
+	return units[u].metres / units[to].metres, nil
+}
+
+type Length struct {
+	Value float64
+	Unit  Unit
+}
+
+// In converts l to u.
+func (l Length) In(u Unit) (Length, error) {
+	factor, err := l.Unit.per(u)
+	if err != nil {
+		return Length{}, err
+	}
+	return Length{l.Value * factor, u}, nil
+}
+
+// Add sums two lengths in the same unit. Convert one with In first to add
+// lengths in different units.
+func (l Length) Add(o Length) (Length, error) {
+	unit, err := sameUnit(l, o)
+	if err != nil {
+		return Length{}, err
+	}
+	return Length{l.Value + o.Value, unit}, nil
+}
+
+func (l Length) String() string {
+	return strconv.FormatFloat(l.Value, 'g', -1, 64) + " " + l.Unit.String()
+}
+
+// Area is measured in the square of Unit.
+type Area struct {
+	Value float64
+	Unit  Unit
+}
+
+// In converts a to the square of u.
+func (a Area) In(u Unit) (Area, error) {
+	factor, err := a.Unit.per(u)
+	if err != nil {
+		return Area{}, err
+	}
+	return Area{a.Value * factor * factor, u}, nil
+}
+
+func (a Area) String() string {
+	return strconv.FormatFloat(a.Value, 'g', -1, 64) + " " + a.Unit.String() + "²"
+}
+
+// Measured is a shape whose dimensions are all in Unit, so its area and
+// perimeter are too.
+type Measured struct {
+	Shape Shape
+	Unit  Unit
+}
+
+func (m Measured) Area() Area {
+	return Area{m.Shape.Area(), m.Unit}
+}
+
+func (m Measured) Perimeter() Length {
+	return Length{m.Shape.Perimeter(), m.Unit}
+}
+
+func MeasuredRectangle(width, height Length) (Measured, error) {
+	return measure(func() (Shape, error) { return NewRectangle(width.Value, height.Value) }, width, height)
+}
+
+func MeasuredSquare(side Length) (Measured, error) {
+	return measure(func() (Shape, error) { return NewSquare(side.Value) }, side)
+}
+
+func MeasuredCircle(radius Length) (Measured, error) {
+	return measure(func() (Shape, error) { return NewCircle(radius.Value) }, radius)
+}
+
+func MeasuredEllipse(radiusX, radiusY Length) (Measured, error) {
+	return measure(func() (Shape, error) { return NewEllipse(radiusX.Value, radiusY.Value) }, radiusX, radiusY)
+}
+
+func MeasuredTriangle(base, height Length) (Measured, error) {
+	return measure(func() (Shape, error) { return NewTriangle(base.Value, height.Value) }, base, height)
+}
+
+func MeasuredSidedTriangle(a, b, c Length) (Measured, error) {
+	return measure(func() (Shape, error) { return NewSidedTriangle(a.Value, b.Value, c.Value) }, a, b, c)
+}
+
+func MeasuredRegularPolygon(sides int, sideLength Length) (Measured, error) {
+	return measure(func() (Shape, error) { return NewRegularPolygon(sides, sideLength.Value) }, sideLength)
+}
+
+// measure checks the lengths share a unit before building the shape from
+// their values.
+func measure(build func() (Shape, error), lengths ...Length) (Measured, error) {
+	unit, err := sameUnit(lengths...)
+	if err != nil {
+		return Measured{}, err
+	}
+	shape, err := build()
+	if err != nil {
+		return Measured{}, err
+	}
+	return Measured{shape, unit}, nil
+}
+
+func sameUnit(lengths ...Length) (Unit, error) {
+	unit := lengths[0].Unit
+	for _, l := range lengths {
+		if !l.Unit.valid() {
+			return 0, fmt.Errorf("%w: %v has no unit", ErrUnknownUnit, l.Value)
+		}
+		if l.Unit != unit {
+			return 0, fmt.Errorf("%w: %v and %v", ErrMixedUnits, lengths[0], l)
+		}
+	}
+	return unit, nil
+}
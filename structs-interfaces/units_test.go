@@ -0,0 +1,151 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestLength(t *testing.T) {
+	t.Run("conversions", func(t *testing.T) {
+		cases := []struct {
+			from Length
+			to   Unit
+			want float64
+		}{
+			{Length{1, Foot}, Inch, 12},
+			{Length{1, Inch}, Millimetre, 25.4},
+			{Length{2.5, Metre}, Centimetre, 250},
+			{Length{3, Foot}, Metre, 0.9144},
+			{Length{7, Millimetre}, Millimetre, 7},
+		}
+
+		for _, c := range cases {
+			got, err := c.from.In(c.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Unit != c.to || !almostEqual(got.Value, c.want) {
+				t.Errorf("%v in %v: got %v want %g %v", c.from, c.to, got, c.want, c.to)
+			}
+		}
+	})
+
+	t.Run("adding", func(t *testing.T) {
+		half, _ := Length{50, Centimetre}.In(Metre)
+		sum, err := Length{1, Metre}.Add(half)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sum != (Length{1.5, Metre}) {
+			t.Errorf("got %v want 1.5 m", sum)
+		}
+
+		if _, err := (Length{1, Metre}).Add(Length{1, Foot}); !errors.Is(err, ErrMixedUnits) {
+			t.Errorf("got %v want %v", err, ErrMixedUnits)
+		}
+	})
+
+	t.Run("units", func(t *testing.T) {
+		for _, symbol := range []string{"mm", "cm", "m", "in", "ft"} {
+			u, err := ParseUnit(symbol)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.String() != symbol {
+				t.Errorf("got %v want %s", u, symbol)
+			}
+		}
+
+		if _, err := ParseUnit("yd"); !errors.Is(err, ErrUnknownUnit) {
+			t.Errorf("got %v want %v", err, ErrUnknownUnit)
+		}
+	})
+
+	t.Run("converting invalid units", func(t *testing.T) {
+		conversions := map[string]func() error{
+			"to no unit":      func() error { _, err := Length{1, Metre}.In(Unit(0)); return err },
+			"from no unit":    func() error { _, err := Length{1, Unit(0)}.In(Metre); return err },
+			"between no unit": func() error { _, err := Length{1, Unit(0)}.In(Unit(0)); return err },
+			"unknown unit":    func() error { _, err := Length{1, Foot}.In(Unit(42)); return err },
+			"areas":           func() error { _, err := Area{1, Unit(0)}.In(Metre); return err },
+		}
+		for name, convert := range conversions {
+			if err := convert(); !errors.Is(err, ErrUnknownUnit) {
+				t.Errorf("%s: got %v want %v", name, err, ErrUnknownUnit)
+			}
+		}
+	})
+}
+
+func TestMeasured(t *testing.T) {
+	t.Run("area and perimeter keep the unit", func(t *testing.T) {
+		rectangle, err := MeasuredRectangle(Length{12, Metre}, Length{6, Metre})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := rectangle.Area(); got != (Area{72, Metre}) {
+			t.Errorf("got %v want 72 m²", got)
+		}
+		if got := rectangle.Perimeter(); got != (Length{36, Metre}) {
+			t.Errorf("got %v want 36 m", got)
+		}
+		if got := rectangle.Area().String(); got != "72 m²" {
+			t.Errorf("got %q want %q", got, "72 m²")
+		}
+	})
+
+	t.Run("areas convert by the square of the unit", func(t *testing.T) {
+		square, err := MeasuredSquare(Length{1, Foot})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := square.Area().In(Inch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Unit != Inch || !almostEqual(got.Value, 144) {
+			t.Errorf("got %v want 144 in²", got)
+		}
+	})
+
+	t.Run("converting first allows mixed inputs", func(t *testing.T) {
+		half, _ := Length{50, Centimetre}.In(Metre)
+		circle, err := MeasuredEllipse(Length{1, Metre}, half)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := circle.Area(); got.Unit != Metre || !almostEqual(got.Value, math.Pi/2) {
+			t.Errorf("got %v want %g m²", got, math.Pi/2)
+		}
+	})
+
+	t.Run("mixed units are rejected", func(t *testing.T) {
+		constructors := map[string]func() (Measured, error){
+			"rectangle": func() (Measured, error) { return MeasuredRectangle(Length{3, Metre}, Length{10, Foot}) },
+			"ellipse":   func() (Measured, error) { return MeasuredEllipse(Length{3, Inch}, Length{3, Centimetre}) },
+			"triangle":  func() (Measured, error) { return MeasuredTriangle(Length{3, Metre}, Length{3, Millimetre}) },
+			"sided": func() (Measured, error) {
+				return MeasuredSidedTriangle(Length{3, Foot}, Length{4, Foot}, Length{5, Inch})
+			},
+		}
+
+		for name, construct := range constructors {
+			if _, err := construct(); !errors.Is(err, ErrMixedUnits) {
+				t.Errorf("%s: got %v want %v", name, err, ErrMixedUnits)
+			}
+		}
+	})
+
+	t.Run("invalid lengths are rejected", func(t *testing.T) {
+		if _, err := MeasuredCircle(Length{Value: 2}); !errors.Is(err, ErrUnknownUnit) {
+			t.Errorf("got %v want %v", err, ErrUnknownUnit)
+		}
+		if _, err := MeasuredRegularPolygon(6, Length{-1, Centimetre}); !errors.Is(err, ErrInvalidDimension) {
+			t.Errorf("got %v want %v", err, ErrInvalidDimension)
+		}
+	})
+}
@@ -0,0 +1,410 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrWKTSyntax = errors.New("invalid WKT")
+
+// WKTError says where in the text parsing failed. Line and Column count from
+// 1, with columns in characters.
+type WKTError struct {
+	Offset int
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *WKTError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *WKTError) Unwrap() error {
+	return e.Err
+}
+
+// ParseWKT reads a single geometry from a subset of well-known text:
+//
+//	POINT (x y)                      a Point
+//	POLYGON ((x y, ...))             a Polygon, without holes
+//	MULTIPOLYGON (((x y, ...)), ...) a MultiPolygon
+//	CIRCLE (x y, r)                  a Circle placed with its centre at x y
+//
+// Keywords are case insensitive and polygon rings must be closed, ending on
+// the point they start from. The result is a Point or a Shape.
+func ParseWKT(text string) (interface{}, error) {
+	p := &wktParser{text: text}
+	p.next()
+
+	geometry, err := p.geometry()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != wktEOF {
+		return nil, p.unexpected("end of input")
+	}
+	return geometry, nil
+}
+
+// FormatWKT writes a Point or Shape as well-known text. Numbers are written
+// with as many digits as it takes to read back the same float64. Circles are
+// written as CIRCLE, and other shapes with straight sides as the POLYGON
+// through their outline. Shapes that are stretched into ellipses have no WKT
+// form, and shapes that are not valid give their validation error.
+func FormatWKT(geometry interface{}) (string, error) {
+	if shape, ok := geometry.(Shape); ok {
+		if err := Validate(shape); err != nil {
+			return "", err
+		}
+	}
+	var b strings.Builder
+	switch g := geometry.(type) {
+	case Point:
+		b.WriteString("POINT (")
+		writeWKTPoint(&b, g)
+		b.WriteString(")")
+	case Polygon:
+		b.WriteString("POLYGON ")
+		writeWKTPolygon(&b, g.Points)
+	case MultiPolygon:
+		if len(g.Polygons) == 0 {
+			return "MULTIPOLYGON EMPTY", nil
+		}
+		b.WriteString("MULTIPOLYGON (")
+		for i, polygon := range g.Polygons {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeWKTPolygon(&b, polygon.Points)
+		}
+		b.WriteString(")")
+	case Shape:
+		outline, ok := OutlineOf(g)
+		if !ok {
+			return "", fmt.Errorf("%w: %T", ErrUnknownShape, g)
+		}
+		if !outline.Curved {
+			b.WriteString("POLYGON ")
+			writeWKTPolygon(&b, outline.Points)
+			break
+		}
+		if !outline.Curve.scalesEvenly() {
+			return "", fmt.Errorf("%T is an ellipse, which WKT cannot describe", g)
+		}
+		radius, _ := outline.Curve.Stretch()
+		b.WriteString("CIRCLE (")
+		writeWKTPoint(&b, Point{outline.Curve.E, outline.Curve.F})
+		b.WriteString(", " + formatWKTNumber(radius) + ")")
+	default:
+		return "", fmt.Errorf("%T is not a point or a shape", geometry)
+	}
+	return b.String(), nil
+}
+
+func writeWKTPolygon(b *strings.Builder, points []Point) {
+	b.WriteString("((")
+	for _, p := range points {
+		writeWKTPoint(b, p)
+		b.WriteString(", ")
+	}
+	writeWKTPoint(b, points[0])
+	b.WriteString("))")
+}
+
+func writeWKTPoint(b *strings.Builder, p Point) {
+	b.WriteString(formatWKTNumber(p.X) + " " + formatWKTNumber(p.Y))
+}
+
+func formatWKTNumber(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+type wktKind int
+
+const (
+	wktEOF wktKind = iota
+	wktWord
+	wktNumber
+	wktOpen
+	wktClose
+	wktComma
+	wktInvalid
+)
+
+type wktToken struct {
+	kind   wktKind
+	text   string
+	offset int
+}
+
+type wktParser struct {
+	text string
+	pos  int
+	tok  wktToken
+}
+
+func (p *wktParser) next() {
+	for p.pos < len(p.text) && strings.IndexByte(" \t\r\n", p.text[p.pos]) >= 0 {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos == len(p.text) {
+		p.tok = wktToken{wktEOF, "", start}
+		return
+	}
+
+	c := p.text[p.pos]
+	switch {
+	case c == '(':
+		p.pos++
+		p.tok = wktToken{wktOpen, "(", start}
+	case c == ')':
+		p.pos++
+		p.tok = wktToken{wktClose, ")", start}
+	case c == ',':
+		p.pos++
+		p.tok = wktToken{wktComma, ",", start}
+	case isWKTLetter(c):
+		for p.pos < len(p.text) && isWKTLetter(p.text[p.pos]) {
+			p.pos++
+		}
+		p.tok = wktToken{wktWord, p.text[start:p.pos], start}
+	case c == '-' || c == '+' || c == '.' || isWKTDigit(c):
+		for p.pos < len(p.text) && (isWKTDigit(p.text[p.pos]) || strings.IndexByte("+-.eE", p.text[p.pos]) >= 0) {
+			p.pos++
+		}
+		p.tok = wktToken{wktNumber, p.text[start:p.pos], start}
+	default:
+		_, size := utf8.DecodeRuneInString(p.text[p.pos:])
+		p.pos += size
+		p.tok = wktToken{wktInvalid, p.text[start:p.pos], start}
+	}
+}
+
+func isWKTLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isWKTDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func (p *wktParser) errorAt(offset int, err error) error {
+	line, lineStart := 1, 0
+	for i := 0; i < offset; i++ {
+		if p.text[i] == '\n' {
+			line, lineStart = line+1, i+1
+		}
+	}
+	return &WKTError{
+		Offset: offset,
+		Line:   line,
+		Column: utf8.RuneCountInString(p.text[lineStart:offset]) + 1,
+		Err:    err,
+	}
+}
+
+func (p *wktParser) unexpected(want string) error {
+	found := strconv.Quote(p.tok.text)
+	if p.tok.kind == wktEOF {
+		found = "end of input"
+	}
+	return p.errorAt(p.tok.offset, fmt.Errorf("%w: expected %s, found %s", ErrWKTSyntax, want, found))
+}
+
+func (p *wktParser) expect(kind wktKind, want string) error {
+	if p.tok.kind != kind {
+		return p.unexpected(want)
+	}
+	p.next()
+	return nil
+}
+
+// empty consumes EMPTY if it is next.
+func (p *wktParser) empty() bool {
+	if p.tok.kind == wktWord && strings.EqualFold(p.tok.text, "EMPTY") {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) geometry() (interface{}, error) {
+	if p.tok.kind != wktWord {
+		return nil, p.unexpected("a geometry type")
+	}
+	keyword := p.tok
+	p.next()
+	if p.tok.kind == wktWord && !strings.EqualFold(p.tok.text, "EMPTY") {
+		return nil, p.errorAt(p.tok.offset, fmt.Errorf("%w: only x y coordinates are supported, found %s", ErrWKTSyntax, p.tok.text))
+	}
+
+	switch strings.ToUpper(keyword.text) {
+	case "POINT":
+		return p.point()
+	case "POLYGON":
+		return p.polygon()
+	case "MULTIPOLYGON":
+		return p.multiPolygon()
+	case "CIRCLE":
+		return p.circle()
+	default:
+		return nil, p.errorAt(keyword.offset, fmt.Errorf("%w: unsupported geometry type %s", ErrWKTSyntax, keyword.text))
+	}
+}
+
+func (p *wktParser) point() (Point, error) {
+	if p.tok.kind == wktWord {
+		return Point{}, p.errorAt(p.tok.offset, fmt.Errorf("%w: empty points are not supported", ErrWKTSyntax))
+	}
+	if err := p.expect(wktOpen, `"("`); err != nil {
+		return Point{}, err
+	}
+	point, err := p.coordinate()
+	if err != nil {
+		return Point{}, err
+	}
+	return point, p.expect(wktClose, `")"`)
+}
+
+func (p *wktParser) polygon() (Polygon, error) {
+	start := p.tok.offset
+	if p.empty() {
+		return Polygon{}, p.errorAt(start, fmt.Errorf("%w: empty polygons are not supported", ErrWKTSyntax))
+	}
+	return p.polygonText()
+}
+
+func (p *wktParser) polygonText() (Polygon, error) {
+	if err := p.expect(wktOpen, `"("`); err != nil {
+		return Polygon{}, err
+	}
+	start := p.tok.offset
+	points, err := p.ring()
+	if err != nil {
+		return Polygon{}, err
+	}
+	if p.tok.kind == wktComma {
+		p.next()
+		return Polygon{}, p.errorAt(p.tok.offset, fmt.Errorf("%w: polygons with holes are not supported", ErrWKTSyntax))
+	}
+	if err := p.expect(wktClose, `")"`); err != nil {
+		return Polygon{}, err
+	}
+
+	polygon, err := NewPolygon(points...)
+	if err != nil {
+		return Polygon{}, p.errorAt(start, err)
+	}
+	return polygon, nil
+}
+
+// ring reads a closed ring of points, returning them without the repeated
+// last point.
+func (p *wktParser) ring() ([]Point, error) {
+	start := p.tok.offset
+	if err := p.expect(wktOpen, `"("`); err != nil {
+		return nil, err
+	}
+	var points []Point
+	for {
+		point, err := p.coordinate()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+		if p.tok.kind != wktComma {
+			break
+		}
+		p.next()
+	}
+	end := p.tok.offset
+	if err := p.expect(wktClose, `"," or ")"`); err != nil {
+		return nil, err
+	}
+
+	if len(points) < 4 {
+		return nil, p.errorAt(start, fmt.Errorf("%w: a ring needs at least 4 points, found %d", ErrWKTSyntax, len(points)))
+	}
+	if points[0] != points[len(points)-1] {
+		return nil, p.errorAt(end, fmt.Errorf("%w: ring does not end where it starts", ErrWKTSyntax))
+	}
+	return points[:len(points)-1], nil
+}
+
+func (p *wktParser) multiPolygon() (MultiPolygon, error) {
+	if p.empty() {
+		return MultiPolygon{}, nil
+	}
+	if err := p.expect(wktOpen, `"("`); err != nil {
+		return MultiPolygon{}, err
+	}
+	var m MultiPolygon
+	for {
+		polygon, err := p.polygonText()
+		if err != nil {
+			return MultiPolygon{}, err
+		}
+		m.Polygons = append(m.Polygons, polygon)
+		if p.tok.kind != wktComma {
+			break
+		}
+		p.next()
+	}
+	return m, p.expect(wktClose, `"," or ")"`)
+}
+
+func (p *wktParser) circle() (Placed, error) {
+	if err := p.expect(wktOpen, `"("`); err != nil {
+		return Placed{}, err
+	}
+	centre, err := p.coordinate()
+	if err != nil {
+		return Placed{}, err
+	}
+	if err := p.expect(wktComma, `","`); err != nil {
+		return Placed{}, err
+	}
+	start := p.tok.offset
+	radius, err := p.number()
+	if err != nil {
+		return Placed{}, err
+	}
+	circle, err := NewCircle(radius)
+	if err != nil {
+		return Placed{}, p.errorAt(start, err)
+	}
+	return Place(circle, Translate(centre.X, centre.Y)), p.expect(wktClose, `")"`)
+}
+
+func (p *wktParser) coordinate() (Point, error) {
+	x, err := p.number()
+	if err != nil {
+		return Point{}, err
+	}
+	y, err := p.number()
+	if err != nil {
+		return Point{}, err
+	}
+	if p.tok.kind == wktNumber {
+		return Point{}, p.errorAt(p.tok.offset, fmt.Errorf("%w: only x y coordinates are supported", ErrWKTSyntax))
+	}
+	return Point{x, y}, nil
+}
+
+func (p *wktParser) number() (float64, error) {
+	if p.tok.kind != wktNumber {
+		return 0, p.unexpected("a number")
+	}
+	v, err := strconv.ParseFloat(p.tok.text, 64)
+	if err != nil || math.IsInf(v, 0) {
+		return 0, p.errorAt(p.tok.offset, fmt.Errorf("%w: %q is not a valid number", ErrWKTSyntax, p.tok.text))
+	}
+	p.next()
+	return v, nil
+}
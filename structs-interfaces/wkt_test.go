@@ -0,0 +1,149 @@
+package structs_interfaces
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseWKT(t *testing.T) {
+	t.Run("geometries", func(t *testing.T) {
+		cases := []struct {
+			text string
+			want interface{}
+		}{
+			{"POINT (1 2)", Point{1, 2}},
+			{"point(-1.5 2e3)", Point{-1.5, 2000}},
+			{"POLYGON ((0 0, 4 0, 4 4, 0 0))", Polygon{[]Point{{0, 0}, {4, 0}, {4, 4}}}},
+			{
+				"MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)),\n  ((5 5, 6 5, 6 6, 5 5)))",
+				MultiPolygon{[]Polygon{{[]Point{{0, 0}, {1, 0}, {1, 1}}}, {[]Point{{5, 5}, {6, 5}, {6, 6}}}}},
+			},
+			{"MULTIPOLYGON EMPTY", MultiPolygon{}},
+			{"CIRCLE (3 4, 2)", Place(Circle{2}, Translate(3, 4))},
+		}
+
+		for _, c := range cases {
+			got, err := ParseWKT(c.text)
+			if err != nil {
+				t.Fatalf("%q: %v", c.text, err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("%q: got %#v want %#v", c.text, got, c.want)
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		cases := []struct {
+			text   string
+			line   int
+			column int
+			want   error
+		}{
+			{"", 1, 1, ErrWKTSyntax},
+			{"LINESTRING (0 0, 1 1)", 1, 1, ErrWKTSyntax},
+			{"POINT (1)", 1, 9, ErrWKTSyntax},
+			{"POINT (1 2 3)", 1, 12, ErrWKTSyntax},
+			{"POINT Z (1 2 3)", 1, 7, ErrWKTSyntax},
+			{"POINT (1 2) extra", 1, 13, ErrWKTSyntax},
+			{"POINT (1 x)", 1, 10, ErrWKTSyntax},
+			{"POINT (1 1.2.3)", 1, 10, ErrWKTSyntax},
+			{"POLYGON ((0 0, 1 0, 1 1, 0 1))", 1, 29, ErrWKTSyntax},
+			{"POLYGON ((0 0, 1 0, 0 0))", 1, 10, ErrWKTSyntax},
+			{"POLYGON ((0 0, 1 0, 2 0, 0 0))", 1, 10, ErrDegeneratePolygon},
+			{"POLYGON ((0 0, 4 0, 4 4, 0 0), (1 1, 2 1, 2 2, 1 1))", 1, 32, ErrWKTSyntax},
+			{"MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)),\n  ((5 5, 6 5 6 6, 5 5)))", 2, 14, ErrWKTSyntax},
+			{"CIRCLE (0 0, -1)", 1, 14, ErrInvalidDimension},
+			{"POINT (1 2é)", 1, 11, ErrWKTSyntax},
+			{"POINT (1é 2)", 1, 9, ErrWKTSyntax},
+		}
+
+		for _, c := range cases {
+			_, err := ParseWKT(c.text)
+
+			var wktErr *WKTError
+			if !errors.As(err, &wktErr) {
+				t.Errorf("%q: got %v want a *WKTError", c.text, err)
+				continue
+			}
+			if wktErr.Line != c.line || wktErr.Column != c.column || !errors.Is(err, c.want) {
+				t.Errorf("%q: got %v want line %d, column %d: %v", c.text, err, c.line, c.column, c.want)
+			}
+		}
+	})
+}
+
+func TestFormatWKT(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		geometries := []interface{}{
+			Point{0.1, -1e-300},
+			Polygon{lShape},
+			Polygon{[]Point{{0, 0}, {1.0 / 3, 0}, {0, math.Pi}}},
+			MultiPolygon{[]Polygon{{square}, {lShape}}},
+			MultiPolygon{},
+			Place(Circle{0.7}, Translate(1.0/3, -2)),
+		}
+
+		for _, g := range geometries {
+			text, err := FormatWKT(g)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ParseWKT(text)
+			if err != nil {
+				t.Fatalf("%q: %v", text, err)
+			}
+			if !reflect.DeepEqual(got, g) {
+				t.Errorf("%q: got %#v want %#v", text, got, g)
+			}
+		}
+	})
+
+	t.Run("other shapes", func(t *testing.T) {
+		cases := []struct {
+			shape Shape
+			want  string
+		}{
+			{Rectangle{4, 2}, "POLYGON ((0 0, 4 0, 4 2, 0 2, 0 0))"},
+			{Circle{2}, "CIRCLE (0 0, 2)"},
+			{Place(Square{1}, Translate(2, 3)), "POLYGON ((2 3, 3 3, 3 4, 2 4, 2 3))"},
+		}
+
+		for _, c := range cases {
+			got, err := FormatWKT(c.shape)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got %q want %q", got, c.want)
+			}
+		}
+
+		if _, err := FormatWKT(Ellipse{2, 1}); err == nil {
+			t.Error("expected an error for an ellipse")
+		}
+		if _, err := FormatWKT(blob{}); !errors.Is(err, ErrUnknownShape) {
+			t.Errorf("got %v want %v", err, ErrUnknownShape)
+		}
+	})
+
+	t.Run("invalid shapes", func(t *testing.T) {
+		cases := []struct {
+			shape Shape
+			want  error
+		}{
+			{Polygon{}, ErrDegeneratePolygon},
+			{MultiPolygon{[]Polygon{{}}}, ErrDegeneratePolygon},
+			{RegularPolygon{2, 1}, ErrInvalidDimension},
+			{Place(Polygon{collinear}, Translate(1, 1)), ErrDegeneratePolygon},
+		}
+
+		for _, c := range cases {
+			if _, err := FormatWKT(c.shape); !errors.Is(err, c.want) {
+				t.Errorf("%v: got %v want %v", c.shape, err, c.want)
+			}
+		}
+	})
+}